@@ -4,11 +4,15 @@ replace github.com/Sirupsen/logrus v1.1.0 => github.com/sirupsen/logrus v1.1.0
 
 require (
 	github.com/allegro/bigcache v1.1.0 // indirect
+	github.com/deckarep/golang-set v0.0.0-20180927150649-699df6a3acf6 // indirect
 	github.com/ethereum/go-ethereum v1.8.20
 	github.com/marcusolsson/tui-go v0.4.0
 	github.com/mitchellh/go-homedir v0.0.0-20180523094522-3864e76763d9
+	github.com/pborman/uuid v0.0.0-20160216163710-c55201b03606 // indirect
+	github.com/rjeczalik/notify v0.9.2 // indirect
 	github.com/sonm-io/core v0.4.21-0.20190114165212-9fe1f213b768
 	go.uber.org/atomic v0.0.0-20170719224650-70bd1261d36b
+	golang.org/x/crypto v0.0.0-20180927165925-5295e8364332
 	google.golang.org/grpc v1.17.0
 	gopkg.in/yaml.v2 v2.2.1
 )
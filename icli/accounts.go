@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/config"
+	"github.com/3Hren/sonmui/icli/internal/logging"
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/notify"
+	"github.com/3Hren/sonmui/icli/internal/wallet"
+	"github.com/3Hren/sonmui/icli/internal/widgets"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/marcusolsson/tui-go"
+	"github.com/mitchellh/go-homedir"
+)
+
+// AccountsController drives account creation, import, export, renaming and
+// removal flows using a sequence of dialogs.
+//
+// Created and imported accounts are registered in the config, which is saved
+// immediately, as well as any changes of account metadata.
+type AccountsController struct {
+	cfg        *config.Config
+	configPath string
+	dialogs    *widgets.DialogStack
+	notify     *notify.Center
+	log        *logging.Logger
+	router     *mp.Router
+
+	// OnAdded is emitted with the address of each created or imported
+	// account.
+	OnAdded *mp.Signal
+	// OnChanged is emitted with the address of each account that is renamed,
+	// removed or used.
+	OnChanged *mp.Signal
+}
+
+func NewAccountsController(cfg *config.Config, configPath string, dialogs *widgets.DialogStack, notifications *notify.Center, log *logging.Logger, router *mp.Router) *AccountsController {
+	return &AccountsController{
+		cfg:        cfg,
+		configPath: configPath,
+		dialogs:    dialogs,
+		notify:     notifications,
+		log:        log,
+		router:     router,
+		OnAdded:    router.NewSignal(),
+		OnChanged:  router.NewSignal(),
+	}
+}
+
+// Create generates a new key.
+func (m *AccountsController) Create() {
+	m.promptKeystore("Create Account", func(dir string) {
+		m.promptNewPassword("Create Account", "", func(password string) {
+			m.runAsync("create account", dir, func() (common.Address, error) {
+				return wallet.Create(dir, password)
+			})
+		})
+	})
+}
+
+// Import stores either a raw private key or a JSON keyfile in the keystore.
+func (m *AccountsController) Import() {
+	dialog := widgets.NewChoiceDialog(m.router, "Import Account", "What would you like to import?", "Raw private key", "JSON keyfile")
+	dialog.OnResult.Connect(func(v interface{}) {
+		result := v.(*widgets.DialogResult)
+		if !result.Accepted {
+			return
+		}
+
+		switch result.Choice {
+		case 0:
+			m.importRawKey()
+		case 1:
+			m.importKeyfile()
+		}
+	})
+	m.dialogs.Push(dialog)
+}
+
+func (m *AccountsController) importRawKey() {
+	m.prompt("Import Account", "Enter hex-encoded private key.", "", tui.EchoModePassword, func(hexKey string) {
+		m.promptKeystore("Import Account", func(dir string) {
+			m.promptNewPassword("Import Account", "", func(password string) {
+				m.runAsync("import account", dir, func() (common.Address, error) {
+					return wallet.ImportHexKey(dir, hexKey, password)
+				})
+			})
+		})
+	})
+}
+
+func (m *AccountsController) importKeyfile() {
+	m.prompt("Import Account", "Enter path to the JSON keyfile.", "", tui.EchoModeNormal, func(path string) {
+		keyJSON, err := wallet.ReadKeyfile(path)
+		if err != nil {
+			m.notify.Error("Failed to read keyfile: %v", err)
+			return
+		}
+
+		m.prompt("Import Account", "Enter password of the keyfile.", "", tui.EchoModePassword, func(password string) {
+			m.promptKeystore("Import Account", func(dir string) {
+				m.promptNewPassword("Import Account", "", func(newPassword string) {
+					m.runAsync("import account", dir, func() (common.Address, error) {
+						return wallet.ImportKeyfile(dir, keyJSON, password, newPassword)
+					})
+				})
+			})
+		})
+	})
+}
+
+// Export backs up the key of one of configured accounts into a JSON keyfile.
+func (m *AccountsController) Export() {
+	accounts := make([]string, 0, len(m.cfg.AccountPaths))
+	for account := range m.cfg.AccountPaths {
+		accounts = append(accounts, account.Hex())
+	}
+	sort.Strings(accounts)
+
+	if len(accounts) == 0 {
+		m.notify.Warn("There are no accounts to export")
+		return
+	}
+
+	dialog := widgets.NewChoiceDialog(m.router, "Export Account", "Select account to export.", accounts...)
+	dialog.OnResult.Connect(func(v interface{}) {
+		result := v.(*widgets.DialogResult)
+		if !result.Accepted || result.Choice < 0 {
+			return
+		}
+
+		account := common.HexToAddress(accounts[result.Choice])
+		dir := m.cfg.AccountPaths[account]
+
+		m.prompt("Export Account", fmt.Sprintf("Enter password of %s.", account.Hex()), "", tui.EchoModePassword, func(password string) {
+			m.promptNewPassword("Export Account", "The keyfile will be encrypted with the new password. ", func(newPassword string) {
+				m.prompt("Export Account", "Enter path to save the keyfile to.", fmt.Sprintf("~/%s.json", account.Hex()), tui.EchoModeNormal, func(path string) {
+					go func() {
+						err := wallet.ExportFile(dir, account, password, newPassword, path)
+
+						m.router.Execute(func() {
+							if err != nil {
+								m.log.Error("failed to export account", logging.F("account", account.Hex()), logging.Err(err))
+								m.notify.Error("Failed to export %s: %v", account.Hex(), err)
+								return
+							}
+
+							m.log.Info("account exported", logging.F("account", account.Hex()), logging.F("path", path))
+							m.notify.Success("Account %s exported to %s", account.Hex(), path)
+						})
+					}()
+				})
+			})
+		})
+	})
+	m.dialogs.Push(dialog)
+}
+
+// Rename asks for the new label of the account.
+func (m *AccountsController) Rename(account common.Address) {
+	text := fmt.Sprintf("Enter label for %s, leave empty to remove it.", account.Hex())
+	m.prompt("Rename Account", text, m.cfg.AccountInfo[account].Label, tui.EchoModeNormal, func(label string) {
+		label = strings.TrimSpace(label)
+		m.update(account, func(info *config.AccountInfo) {
+			info.Label = label
+		})
+		m.OnChanged.Emit(account)
+	})
+}
+
+// Remove forgets the account after confirmation, leaving its keyfile in the
+// keystore.
+func (m *AccountsController) Remove(account common.Address) {
+	text := fmt.Sprintf("Remove %s from the list of accounts? The keyfile stays in %s.", account.Hex(), m.cfg.AccountPaths[account])
+	dialog := widgets.NewConfirmDialog(m.router, "Remove Account", text)
+	dialog.OnResult.Connect(func(v interface{}) {
+		if !v.(*widgets.DialogResult).Accepted {
+			return
+		}
+
+		if err := config.RemoveAccount(m.configPath, m.cfg, account); err != nil {
+			m.log.Error("failed to save config", logging.F("path", m.configPath), logging.Err(err))
+			m.notify.Warn("Account %s is removed, but the config is not saved: %v", account.Hex(), err)
+		} else {
+			m.log.Info("account removed", logging.F("account", account.Hex()))
+			m.notify.Success("Account %s is removed", account.Hex())
+		}
+
+		m.OnChanged.Emit(account)
+	})
+	m.dialogs.Push(dialog)
+}
+
+// Touch records that the account is used now, which moves it to the top of
+// the list of accounts. Accounts missing in the config are ignored.
+func (m *AccountsController) Touch(account common.Address) {
+	if _, ok := m.cfg.AccountPaths[account]; !ok {
+		return
+	}
+
+	m.update(account, func(info *config.AccountInfo) {
+		info.LastUsed = time.Now()
+	})
+	m.OnChanged.Emit(account)
+}
+
+func (m *AccountsController) update(account common.Address, fn func(info *config.AccountInfo)) {
+	if err := config.UpdateAccount(m.configPath, m.cfg, account, fn); err != nil {
+		m.log.Error("failed to save config", logging.F("path", m.configPath), logging.Err(err))
+		m.notify.Warn("Failed to save config: %v", err)
+	}
+}
+
+func (m *AccountsController) prompt(title, text, value string, echoMode tui.EchoMode, fn func(text string)) {
+	dialog := widgets.NewInputDialog(m.router, title, text, echoMode)
+	dialog.SetText(value)
+	dialog.OnResult.Connect(func(v interface{}) {
+		if result := v.(*widgets.DialogResult); result.Accepted {
+			fn(result.Text)
+		}
+	})
+	m.dialogs.Push(dialog)
+}
+
+func (m *AccountsController) promptKeystore(title string, fn func(dir string)) {
+	m.prompt(title, "Enter keystore directory to store the key in.", wallet.DefaultKeystorePath, tui.EchoModeNormal, func(dir string) {
+		dir, err := homedir.Expand(dir)
+		if err != nil {
+			m.notify.Error("Invalid keystore directory: %v", err)
+			return
+		}
+
+		fn(filepath.Clean(dir))
+	})
+}
+
+// promptNewPassword asks for a new password and its confirmation, asking
+// again until the password is strong enough and confirmed correctly.
+func (m *AccountsController) promptNewPassword(title, text string, fn func(password string)) {
+	m.prompt(title, text+"Enter new password.", "", tui.EchoModePassword, func(password string) {
+		if err := wallet.CheckPassword(password); err != nil {
+			m.promptNewPassword(title, fmt.Sprintf("Weak password: %v. ", err), fn)
+			return
+		}
+
+		m.prompt(title, "Confirm the password.", "", tui.EchoModePassword, func(confirmation string) {
+			if err := wallet.CheckNewPassword(password, confirmation); err != nil {
+				m.promptNewPassword(title, fmt.Sprintf("Error: %v. ", err), fn)
+				return
+			}
+
+			fn(password)
+		})
+	})
+}
+
+// runAsync runs the given keystore operation, which is slow due to key
+// derivation, in the background and registers the resulting account.
+func (m *AccountsController) runAsync(operation, dir string, fn func() (common.Address, error)) {
+	m.notify.Info("Please wait, encrypting the key...")
+
+	go func() {
+		account, err := fn()
+
+		m.router.Execute(func() {
+			if err != nil {
+				m.log.Error("failed to "+operation, logging.Err(err))
+				m.notify.Error("Failed to %s: %v", operation, err)
+				return
+			}
+
+			m.register(account, dir)
+		})
+	}()
+}
+
+func (m *AccountsController) register(account common.Address, dir string) {
+	m.cfg.AccountPaths[account] = dir
+	m.log.Info("account added", logging.F("account", account.Hex()), logging.F("keystore", dir))
+
+	if err := config.AddAccount(m.configPath, account, dir); err != nil {
+		m.log.Error("failed to save config", logging.F("path", m.configPath), logging.Err(err))
+		m.notify.Warn("Account %s is added, but the config is not saved: %v", account.Hex(), err)
+	} else {
+		m.notify.Success("Account %s is added", account.Hex())
+	}
+
+	m.OnAdded.Emit(account)
+}
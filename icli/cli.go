@@ -0,0 +1,733 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/3Hren/sonmui/icli/internal/addressbook"
+	"github.com/3Hren/sonmui/icli/internal/config"
+	"github.com/3Hren/sonmui/icli/internal/logging"
+	"github.com/3Hren/sonmui/icli/internal/rpc"
+	"github.com/3Hren/sonmui/icli/internal/signer"
+	"github.com/3Hren/sonmui/icli/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/marcusolsson/tui-go"
+	"github.com/mitchellh/go-homedir"
+	"github.com/sonm-io/core/insonmnia/version"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const usageText = `Usage: icli [flags] [command]
+
+Without a command the interactive UI is started.
+
+Commands:
+  version                  print version and exit
+  accounts list            list configured accounts
+  accounts create          generate a new key in the keystore
+  accounts import FILE     import JSON keyfile or raw hex-encoded key from file
+  accounts export ADDRESS FILE
+                           back up the key of the account into JSON keyfile
+  workers list             list workers of the account
+  workers confirm ADDRESS  confirm the worker with the given address
+  balance                  show balance of the account
+  orders                   list active orders of the account
+  deals                    list accepted deals of the account
+  addressbook list         list named addresses
+  addressbook set ADDRESS NAME [NOTE]
+                           name the address, shown instead of it in tables
+  addressbook remove ADDRESS
+                           forget the name of the address
+
+Exit codes:
+  0  success
+  1  unclassified failure
+  2  invalid usage
+  3  node is unavailable or did not respond in time
+  4  account is not authorized to perform the request
+  5  requested entity is not found
+  6  request is rejected by the node as invalid
+  7  node failed to handle the request
+
+Flags:
+`
+
+const (
+	exitOK           = 0
+	exitFailure      = 1
+	exitUsage        = 2
+	exitUnavailable  = 3
+	exitUnauthorized = 4
+	exitNotFound     = 5
+	exitRejected     = 6
+	exitInternal     = 7
+)
+
+// usageError indicates that the command line is malformed.
+type usageError struct {
+	error
+}
+
+// exitCode maps the error returned by a command into the process exit code,
+// allowing scripts to distinguish failures without parsing messages.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	if _, ok := err.(*usageError); ok {
+		return exitUsage
+	}
+
+	switch rpc.Classify(err) {
+	case rpc.ClassUnavailable:
+		return exitUnavailable
+	case rpc.ClassUnauthorized:
+		return exitUnauthorized
+	case rpc.ClassNotFound:
+		return exitNotFound
+	case rpc.ClassRejected:
+		return exitRejected
+	case rpc.ClassInternal:
+		return exitInternal
+	default:
+		return exitFailure
+	}
+}
+
+// options contains command-line flags shared by the interactive UI and
+// non-interactive commands.
+type options struct {
+	ConfigPath string
+	Node       string
+	Account    string
+	Keystore   string
+	Theme      string
+	LogFile    string
+	LogLevel   string
+	Output     string
+
+	Signer       string
+	KeyEnv       string
+	SignerSocket string
+}
+
+func parseOptions(args []string) (*options, []string, error) {
+	opts := &options{}
+
+	flags := flag.NewFlagSet("icli", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usageText)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.ConfigPath, "config", config.DefaultConfigPath, "path to the config file")
+	flags.StringVar(&opts.Node, "node", "", "node gRPC endpoint, overrides the one from config")
+	flags.StringVar(&opts.Account, "account", "", "account address to use")
+	flags.StringVar(&opts.Keystore, "keystore", "", "keystore directory of the account")
+	flags.StringVar(&opts.Theme, "theme", "", "theme name or path to a theme file, overrides the one from config")
+	flags.StringVar(&opts.LogFile, "log-file", logging.DefaultLogPath, "path to the log file, logging to file is disabled when empty")
+	flags.StringVar(&opts.LogLevel, "log-level", "info", "minimum level of logged events: debug, info, warn or error")
+	flags.StringVar(&opts.Output, "output", outputTable, "output format of commands: "+strings.Join(outputFormats, ", "))
+	flags.StringVar(&opts.Signer, "signer", signer.KindKeystore, "how to sign on behalf of the account: "+strings.Join(signer.Kinds, ", "))
+	flags.StringVar(&opts.KeyEnv, "key-env", signer.DefaultKeyEnv, "environment variable with hex-encoded private key for the env signer")
+	flags.StringVar(&opts.SignerSocket, "signer-socket", signer.DefaultSocketPath, "Unix socket of the external signer")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	return opts, flags.Args(), nil
+}
+
+// loadConfig loads the config file, falling back to defaults when it does not
+// exist, and applies command-line overrides on top of it.
+func loadConfig(opts *options) (*config.Config, error) {
+	cfg, err := config.LoadConfig(opts.ConfigPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		cfg = config.NewConfig()
+	}
+
+	if opts.Node != "" {
+		// The flag wins over nodes configured for particular accounts too.
+		cfg.Node = opts.Node
+		for account, info := range cfg.AccountInfo {
+			info.Node = ""
+			cfg.AccountInfo[account] = info
+		}
+	}
+	if opts.Theme != "" {
+		cfg.Theme = opts.Theme
+	}
+
+	if opts.Account != "" {
+		if !common.IsHexAddress(opts.Account) {
+			return nil, fmt.Errorf("invalid account address %q", opts.Account)
+		}
+
+		if opts.Keystore != "" {
+			cfg.AccountPaths[common.HexToAddress(opts.Account)] = opts.Keystore
+		}
+	}
+
+	return cfg, nil
+}
+
+// setupLogging constructs the logger writing to the rotating file at the
+// given path and redirects both standard and tui-go loggers into it.
+func setupLogging(path string, levelName string) (*logging.Logger, io.Closer, error) {
+	level, err := logging.ParseLevel(levelName)
+	if err != nil {
+		return nil, nil, &usageError{err}
+	}
+
+	if path == "" {
+		return logging.NewLogger(nil, level), nil, nil
+	}
+
+	path, err = homedir.Expand(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := logging.OpenRotatingFile(path, logging.DefaultMaxSize, logging.DefaultMaxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger := logging.NewLogger(file, level)
+	log.SetFlags(0)
+	log.SetOutput(logger.Writer(logging.InfoLevel))
+	// tui-go logs every received key event, including typed passwords, so
+	// its log is never written anywhere.
+	tui.SetLogger(log.New(ioutil.Discard, "", 0))
+
+	return logger, file, nil
+}
+
+// execStandaloneCommand runs commands that need neither the config nor the
+// log, so they work even if either is broken. It returns false if the
+// command is not one of them.
+func execStandaloneCommand(args []string) bool {
+	switch {
+	case len(args) == 1 && args[0] == "version":
+		fmt.Println(version.Version)
+		return true
+	default:
+		return false
+	}
+}
+
+func execCommand(ctx context.Context, cfg *config.Config, opts *options, log *logging.Logger, args []string) error {
+	if err := validateOutputFormat(opts.Output); err != nil {
+		return err
+	}
+
+	book, err := addressbook.Load(addressbook.PathFor(opts.ConfigPath))
+	if err != nil {
+		return fmt.Errorf("failed to load address book: %v", err)
+	}
+
+	var res *result
+
+	switch {
+	case len(args) == 2 && args[0] == "accounts" && args[1] == "list":
+		res, err = execAccountsList(cfg)
+	case len(args) == 2 && args[0] == "accounts" && args[1] == "create":
+		res, err = execAccountsCreate(opts)
+	case len(args) == 3 && args[0] == "accounts" && args[1] == "import":
+		res, err = execAccountsImport(opts, args[2])
+	case len(args) == 4 && args[0] == "accounts" && args[1] == "export":
+		if !common.IsHexAddress(args[2]) {
+			return &usageError{fmt.Errorf("invalid account address %q", args[2])}
+		}
+
+		res, err = execAccountsExport(cfg, common.HexToAddress(args[2]), args[3])
+	case len(args) == 2 && args[0] == "workers" && args[1] == "list":
+		res, err = execWithClient(ctx, cfg, opts, log, func(ctx context.Context, client *rpc.Client) (*result, error) {
+			return execWorkersList(ctx, client, book)
+		})
+	case len(args) == 3 && args[0] == "workers" && args[1] == "confirm":
+		if !common.IsHexAddress(args[2]) {
+			return &usageError{fmt.Errorf("invalid worker address %q", args[2])}
+		}
+
+		worker := common.HexToAddress(args[2])
+		res, err = execWithClient(ctx, cfg, opts, log, func(ctx context.Context, client *rpc.Client) (*result, error) {
+			return execWorkerConfirm(ctx, client, worker, book)
+		})
+	case len(args) == 1 && args[0] == "balance":
+		res, err = execWithClient(ctx, cfg, opts, log, execBalance)
+	case len(args) == 1 && args[0] == "orders":
+		res, err = execWithClient(ctx, cfg, opts, log, func(ctx context.Context, client *rpc.Client) (*result, error) {
+			return execOrders(ctx, client, book)
+		})
+	case len(args) == 1 && args[0] == "deals":
+		res, err = execWithClient(ctx, cfg, opts, log, func(ctx context.Context, client *rpc.Client) (*result, error) {
+			return execDeals(ctx, client, book)
+		})
+	case len(args) == 2 && args[0] == "addressbook" && args[1] == "list":
+		res, err = execAddressBookList(book)
+	case (len(args) == 4 || len(args) == 5) && args[0] == "addressbook" && args[1] == "set":
+		if !common.IsHexAddress(args[2]) {
+			return &usageError{fmt.Errorf("invalid address %q", args[2])}
+		}
+
+		entry := addressbook.Entry{Name: args[3]}
+		if len(args) == 5 {
+			entry.Note = args[4]
+		}
+
+		res, err = execAddressBookSet(book, common.HexToAddress(args[2]), entry)
+	case len(args) == 3 && args[0] == "addressbook" && args[1] == "remove":
+		if !common.IsHexAddress(args[2]) {
+			return &usageError{fmt.Errorf("invalid address %q", args[2])}
+		}
+
+		res, err = execAddressBookRemove(book, common.HexToAddress(args[2]))
+	default:
+		return &usageError{fmt.Errorf("unknown command %q, see --help", strings.Join(args, " "))}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return printResult(os.Stdout, opts.Output, res)
+}
+
+// execWithClient connects to the node on behalf of the account specified in
+// options and runs the given command.
+func execWithClient(ctx context.Context, cfg *config.Config, opts *options, log *logging.Logger, fn func(ctx context.Context, client *rpc.Client) (*result, error)) (*result, error) {
+	s, err := newSigner(cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	node := cfg.NodeOf(s.Account())
+	conn, err := rpc.Dial(ctx, node, s)
+	if err != nil {
+		log.Error("failed to connect to the node", logging.F("node", node), logging.Err(err))
+		return nil, err
+	}
+
+	client := rpc.NewClient(conn, s.Account(), log)
+	defer client.Close()
+
+	return fn(ctx, client)
+}
+
+type accountRecord struct {
+	Address  string `json:"address" yaml:"address"`
+	Label    string `json:"label,omitempty" yaml:"label,omitempty"`
+	Keystore string `json:"keystore" yaml:"keystore"`
+}
+
+func execAccountsList(cfg *config.Config) (*result, error) {
+	addrs := cfg.RecentAccounts()
+
+	records := make([]*accountRecord, 0, len(addrs))
+	res := &result{Header: []string{"ACCOUNT", "LABEL", "KEYSTORE"}, Value: &records}
+	for _, addr := range addrs {
+		label := cfg.AccountInfo[addr].Label
+		records = append(records, &accountRecord{Address: addr.Hex(), Label: label, Keystore: cfg.AccountPaths[addr]})
+		res.AddRow(addr.Hex(), label, cfg.AccountPaths[addr])
+	}
+
+	return res, nil
+}
+
+// keystoreDir returns the keystore directory new keys are stored in.
+func keystoreDir(opts *options) (string, error) {
+	dir := opts.Keystore
+	if dir == "" {
+		dir = wallet.DefaultKeystorePath
+	}
+
+	return homedir.Expand(dir)
+}
+
+func execAccountsCreate(opts *options) (*result, error) {
+	dir, err := keystoreDir(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := readNewPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := wallet.Create(dir, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return registerAccount(opts, account, dir)
+}
+
+func execAccountsImport(opts *options, path string) (*result, error) {
+	dir, err := keystoreDir(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := wallet.ReadKeyfile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var account common.Address
+	if wallet.IsKeyfile(content) {
+		password, err := readPassword("Keyfile password: ")
+		if err != nil {
+			return nil, err
+		}
+
+		newPassword, err := readNewPassword()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err = wallet.ImportKeyfile(dir, content, password, newPassword)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		password, err := readNewPassword()
+		if err != nil {
+			return nil, err
+		}
+
+		account, err = wallet.ImportHexKey(dir, string(content), password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return registerAccount(opts, account, dir)
+}
+
+func execAccountsExport(cfg *config.Config, account common.Address, path string) (*result, error) {
+	dir, ok := cfg.AccountPaths[account]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s, specify its keystore using --keystore", account.Hex())
+	}
+
+	password, err := readPassword(fmt.Sprintf("Password for %s: ", account.Hex()))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stderr, "The keyfile will be encrypted with the new password.")
+	newPassword, err := readNewPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wallet.ExportFile(dir, account, password, newPassword, path); err != nil {
+		return nil, err
+	}
+
+	record := &accountRecord{Address: account.Hex(), Keystore: path}
+	res := &result{Header: []string{"ACCOUNT", "KEYFILE"}, Value: record}
+	res.AddRow(record.Address, record.Keystore)
+
+	return res, nil
+}
+
+// registerAccount adds the account to the config file.
+func registerAccount(opts *options, account common.Address, dir string) (*result, error) {
+	if err := config.AddAccount(opts.ConfigPath, account, dir); err != nil {
+		return nil, fmt.Errorf("account %s is stored in %s, but failed to update the config: %v", account.Hex(), dir, err)
+	}
+
+	record := &accountRecord{Address: account.Hex(), Keystore: dir}
+	res := &result{Header: []string{"ACCOUNT", "KEYSTORE"}, Value: record}
+	res.AddRow(record.Address, record.Keystore)
+
+	return res, nil
+}
+
+type workerRecord struct {
+	Address   string `json:"address" yaml:"address"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Confirmed bool   `json:"confirmed" yaml:"confirmed"`
+}
+
+func execWorkersList(ctx context.Context, client *rpc.Client, book *addressbook.Book) (*result, error) {
+	workers, err := client.Workers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*workerRecord, 0, len(workers))
+	res := &result{Header: []string{"WORKER", "CONFIRMED"}, Value: &records}
+	for _, worker := range workers {
+		addr := worker.GetSlaveID().Unwrap()
+		record := &workerRecord{
+			Address:   addr.Hex(),
+			Name:      book.Name(addr),
+			Confirmed: worker.GetConfirmed(),
+		}
+
+		records = append(records, record)
+		res.AddRow(book.Format(addr), strconv.FormatBool(record.Confirmed))
+	}
+
+	return res, nil
+}
+
+func execWorkerConfirm(ctx context.Context, client *rpc.Client, worker common.Address, book *addressbook.Book) (*result, error) {
+	if err := client.ConfirmWorker(ctx, worker); err != nil {
+		return nil, err
+	}
+
+	record := &workerRecord{Address: worker.Hex(), Name: book.Name(worker), Confirmed: true}
+	res := &result{Header: []string{"WORKER", "CONFIRMED"}, Value: record}
+	res.AddRow(book.Format(worker), strconv.FormatBool(record.Confirmed))
+
+	return res, nil
+}
+
+type balanceRecord struct {
+	Account     string `json:"account" yaml:"account"`
+	LiveBalance string `json:"liveBalance" yaml:"liveBalance"`
+	SideBalance string `json:"sideBalance" yaml:"sideBalance"`
+}
+
+func execBalance(ctx context.Context, client *rpc.Client) (*result, error) {
+	balance, err := client.Balance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &balanceRecord{
+		Account:     client.Account().Hex(),
+		LiveBalance: balance.GetLiveBalance().ToPriceString(),
+		SideBalance: balance.GetSideBalance().ToPriceString(),
+	}
+	res := &result{Header: []string{"ACCOUNT", "LIVE", "SIDE"}, Value: record}
+	res.AddRow(record.Account, record.LiveBalance, record.SideBalance)
+
+	return res, nil
+}
+
+type orderRecord struct {
+	ID         string `json:"id" yaml:"id"`
+	Author     string `json:"author" yaml:"author"`
+	AuthorName string `json:"authorName,omitempty" yaml:"authorName,omitempty"`
+	Price      string `json:"price" yaml:"price"`
+	Duration   uint64 `json:"duration" yaml:"duration"`
+}
+
+func execOrders(ctx context.Context, client *rpc.Client, book *addressbook.Book) (*result, error) {
+	orders, err := client.Orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*orderRecord, 0, len(orders))
+	res := &result{Header: []string{"ID", "AUTHOR", "PRICE", "DURATION"}, Value: &records}
+	for _, order := range orders {
+		author := order.GetAuthorID().Unwrap()
+		record := &orderRecord{
+			ID:         order.GetId().Unwrap().String(),
+			Author:     author.Hex(),
+			AuthorName: book.Name(author),
+			Price:      order.GetPrice().ToPriceString(),
+			Duration:   order.GetDuration(),
+		}
+
+		records = append(records, record)
+		res.AddRow(record.ID, book.Format(author), record.Price, strconv.FormatUint(record.Duration, 10))
+	}
+
+	return res, nil
+}
+
+type dealRecord struct {
+	ID           string `json:"id" yaml:"id"`
+	Supplier     string `json:"supplier" yaml:"supplier"`
+	SupplierName string `json:"supplierName,omitempty" yaml:"supplierName,omitempty"`
+	Consumer     string `json:"consumer" yaml:"consumer"`
+	ConsumerName string `json:"consumerName,omitempty" yaml:"consumerName,omitempty"`
+	Price        string `json:"price" yaml:"price"`
+	Status       string `json:"status" yaml:"status"`
+}
+
+func execDeals(ctx context.Context, client *rpc.Client, book *addressbook.Book) (*result, error) {
+	deals, _, err := client.Deals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*dealRecord, 0, len(deals))
+	res := &result{Header: []string{"ID", "SUPPLIER", "CONSUMER", "PRICE", "STATUS"}, Value: &records}
+	for _, deal := range deals {
+		deal := deal.GetDeal()
+		supplier := deal.GetSupplierID().Unwrap()
+		consumer := deal.GetConsumerID().Unwrap()
+		record := &dealRecord{
+			ID:           deal.GetId().Unwrap().String(),
+			Supplier:     supplier.Hex(),
+			SupplierName: book.Name(supplier),
+			Consumer:     consumer.Hex(),
+			ConsumerName: book.Name(consumer),
+			Price:        deal.GetPrice().ToPriceString(),
+			Status:       deal.GetStatus().String(),
+		}
+
+		records = append(records, record)
+		res.AddRow(record.ID, book.Format(supplier), book.Format(consumer), record.Price, record.Status)
+	}
+
+	return res, nil
+}
+
+type addressRecord struct {
+	Address string `json:"address" yaml:"address"`
+	Name    string `json:"name" yaml:"name"`
+	Note    string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+func execAddressBookList(book *addressbook.Book) (*result, error) {
+	records := make([]*addressRecord, 0)
+	res := &result{Header: []string{"ADDRESS", "NAME", "NOTE"}, Value: &records}
+	for _, entry := range book.Records() {
+		record := &addressRecord{Address: entry.Address.Hex(), Name: entry.Name, Note: entry.Note}
+		records = append(records, record)
+		res.AddRow(record.Address, record.Name, record.Note)
+	}
+
+	return res, nil
+}
+
+func execAddressBookSet(book *addressbook.Book, addr common.Address, entry addressbook.Entry) (*result, error) {
+	if err := book.Set(addr, entry); err != nil {
+		return nil, err
+	}
+
+	record := &addressRecord{Address: addr.Hex(), Name: entry.Name, Note: entry.Note}
+	res := &result{Header: []string{"ADDRESS", "NAME", "NOTE"}, Value: record}
+	res.AddRow(record.Address, record.Name, record.Note)
+
+	return res, nil
+}
+
+func execAddressBookRemove(book *addressbook.Book, addr common.Address) (*result, error) {
+	entry, ok := book.Lookup(addr)
+	if !ok {
+		return nil, fmt.Errorf("address %s is not in the address book", addr.Hex())
+	}
+
+	if err := book.Remove(addr); err != nil {
+		return nil, err
+	}
+
+	record := &addressRecord{Address: addr.Hex(), Name: entry.Name, Note: entry.Note}
+	res := &result{Header: []string{"ADDRESS", "NAME", "NOTE"}, Value: record}
+	res.AddRow(record.Address, record.Name, record.Note)
+
+	return res, nil
+}
+
+// newSigner constructs the signer selected by flags.
+//
+// The keystore signer resolves the account from flags or config and decrypts
+// its key using the password read from the terminal or, when stdin is not a
+// terminal, the first line of stdin.
+func newSigner(cfg *config.Config, opts *options) (signer.Signer, error) {
+	switch opts.Signer {
+	case signer.KindKeystore:
+	case signer.KindEnv:
+		return signer.NewEnvSigner(opts.KeyEnv)
+	case signer.KindExternal:
+		return signer.NewExternalSigner(opts.SignerSocket, common.HexToAddress(opts.Account))
+	default:
+		return nil, &usageError{fmt.Errorf("unknown signer %q, expected one of: %s", opts.Signer, strings.Join(signer.Kinds, ", "))}
+	}
+
+	var account common.Address
+
+	switch {
+	case opts.Account != "":
+		account = common.HexToAddress(opts.Account)
+	case len(cfg.AccountPaths) == 1:
+		for addr := range cfg.AccountPaths {
+			account = addr
+		}
+	default:
+		return nil, fmt.Errorf("there are %d accounts configured, specify one using --account", len(cfg.AccountPaths))
+	}
+
+	path, ok := cfg.AccountPaths[account]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s, specify its keystore using --keystore", account.Hex())
+	}
+
+	password, err := readPassword(fmt.Sprintf("Password for %s: ", account.Hex()))
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.NewKeystoreSigner(path, account, password)
+}
+
+// readNewPassword reads the password with confirmation, checking its
+// strength.
+func readNewPassword() (string, error) {
+	password, err := readPassword("New password: ")
+	if err != nil {
+		return "", err
+	}
+
+	confirmation, err := readPassword("Repeat password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := wallet.CheckNewPassword(password, confirmation); err != nil {
+		return "", &usageError{err}
+	}
+
+	return password, nil
+}
+
+// stdin is shared between reads, because buffered data would be lost
+// otherwise when several lines are read.
+var stdin = bufio.NewReader(os.Stdin)
+
+func readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if !terminal.IsTerminal(fd) {
+		line, err := stdin.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(password), nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/notify"
+	"github.com/3Hren/sonmui/icli/internal/session"
+	"github.com/3Hren/sonmui/icli/internal/widgets"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	transferHint = "Enter the recipient and the amount, then press <Enter> on [Transfer]. Press <Esc> to go back."
+	bidOrderHint = "Enter the price, the duration, empty for a spot order, and the resources required, then press <Enter> on [Place]. Press <Esc> to go back."
+)
+
+// bytesPerGiB converts the RAM size of bid orders, entered in GiB, to the
+// bytes benchmarks are measured in.
+const bytesPerGiB = 1 << 30
+
+// FormView shows a form with a title in the middle of the screen, like the
+// password view does.
+type FormView struct {
+	*tui.Box
+
+	form *widgets.Form
+}
+
+func NewFormView(router *mp.Router, title string, fields []widgets.FormField, submitText string) *FormView {
+	titleLabel := tui.NewLabel(title)
+	titleLabel.SetStyleName("title")
+
+	form := widgets.NewForm(router, fields, submitText, "[Cancel]")
+
+	box := tui.NewHBox(
+		tui.NewSpacer(),
+		tui.NewVBox(
+			tui.NewSpacer(),
+			tui.NewPadder(1, 1, titleLabel),
+			tui.NewPadder(1, 0, form),
+			tui.NewSpacer(),
+			tui.NewSpacer(),
+		),
+		tui.NewSpacer(),
+	)
+
+	return &FormView{
+		Box:  box,
+		form: form,
+	}
+}
+
+func (m *FormView) SetFocused(v bool) {
+	m.form.SetFocused(v)
+}
+
+// showForm pushes a view with the form onto the navigator. Once submitted,
+// the password is asked to perform the action and the view is closed before
+// the function is called with the values.
+//
+// Nothing is done if the user leaves the view while authenticating.
+func showForm(view *FormView, hint, action string, navigator *widgets.Navigator, keys *keymap.Keymap, sess *session.Session, dialogs *widgets.DialogStack, notifications *notify.Center, router *mp.Router, fn func(values widgets.FormValues)) {
+	view.form.SetKeymap(keys)
+
+	removed := false
+	navigator.OnRemoved(view, func() {
+		removed = true
+		navigator.RemoveContext(view)
+	})
+
+	view.form.OnSubmit.Connect(func(v interface{}) {
+		values := v.(widgets.FormValues)
+
+		reauthenticate(sess, dialogs, notifications, router, action, func() {
+			if removed {
+				return
+			}
+
+			navigator.Pop()
+			fn(values)
+		})
+	})
+	view.form.OnCancel.Connect(func(interface{}) {
+		navigator.Pop()
+	})
+
+	help := keymap.NewContext()
+	help.Register(keymap.NextFocus, "")
+	help.Register(keymap.PrevFocus, "")
+	view.form.RegisterInputs(help)
+	navigator.SetContext(view, help)
+
+	navigator.Push(view, hint)
+	view.form.FocusDefault()
+}
+
+func transferFields() []widgets.FormField {
+	return []widgets.FormField{
+		{Name: "to", Label: "Recipient", Kind: widgets.AddressField, Required: true},
+		{Name: "amount", Label: "Amount", Kind: widgets.AmountField, Required: true, Validate: validatePositive},
+	}
+}
+
+func bidOrderFields() []widgets.FormField {
+	return []widgets.FormField{
+		{Name: "price", Label: "Price", Kind: widgets.PriceField, Required: true, Validate: validatePositive},
+		{Name: "duration", Label: "Duration", Kind: widgets.DurationField},
+		{Name: "cpu", Label: "CPU cores", Kind: widgets.NumberField, Text: "1", Min: 1, Max: 1024},
+		{Name: "ram", Label: "RAM, GiB", Kind: widgets.NumberField, Text: "1", Min: 1, Max: 4096},
+	}
+}
+
+// bidOrderBenchmarks converts resources entered in the bid order form to
+// benchmarks of the order.
+func bidOrderBenchmarks(values widgets.FormValues) map[string]uint64 {
+	return map[string]uint64{
+		"cpu-cores": uint64(values.Number("cpu")),
+		"ram-size":  uint64(values.Number("ram")) * bytesPerGiB,
+	}
+}
+
+func validatePositive(value interface{}) error {
+	if value.(*big.Int).Sign() <= 0 {
+		return errors.New("must be positive")
+	}
+
+	return nil
+}
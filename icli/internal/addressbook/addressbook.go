@@ -0,0 +1,173 @@
+// Package addressbook maps addresses of counterparties, like suppliers and
+// workers, to human-readable names.
+package addressbook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// FileName is the name of the address book file, which is stored in the
+	// directory of the config file.
+	FileName = "addressbook.yaml"
+)
+
+// Entry describes a single address.
+type Entry struct {
+	Name string `yaml:"name"`
+	Note string `yaml:"note,omitempty"`
+}
+
+// Record is an entry along with its address.
+type Record struct {
+	Address common.Address
+	Entry
+}
+
+// Book is an address book backed by a YAML file, which is saved on each
+// change.
+//
+// The book is safe for concurrent use, since it is read by completion hint
+// providers running outside of the UI thread.
+type Book struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[common.Address]Entry
+}
+
+// PathFor returns the path of the address book stored alongside the config
+// file at the given path.
+func PathFor(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), FileName)
+}
+
+// Load reads the address book at the given path. A missing file results in
+// an empty book, which creates the file when changed.
+func Load(path string) (*Book, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Book{
+		path:    path,
+		entries: map[common.Address]Entry{},
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(content, &m.entries); err != nil {
+		return nil, err
+	}
+
+	if m.entries == nil {
+		m.entries = map[common.Address]Entry{}
+	}
+
+	return m, nil
+}
+
+// Lookup returns the entry of the given address. A nil book is empty.
+func (m *Book) Lookup(addr common.Address) (Entry, bool) {
+	if m == nil {
+		return Entry{}, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.entries[addr]
+	return entry, ok
+}
+
+// Name returns the name of the given address or an empty string.
+func (m *Book) Name(addr common.Address) string {
+	entry, _ := m.Lookup(addr)
+	return entry.Name
+}
+
+// Format returns the name of the address followed by its shortened hex, or
+// the full hex if the address is unknown.
+func (m *Book) Format(addr common.Address) string {
+	name := m.Name(addr)
+	if name == "" {
+		return addr.Hex()
+	}
+
+	return name + " (" + Short(addr) + ")"
+}
+
+// Short returns the address hex with the middle part elided.
+func Short(addr common.Address) string {
+	hex := addr.Hex()
+	return hex[:6] + "…" + hex[len(hex)-4:]
+}
+
+// Records returns all entries sorted by name.
+func (m *Book) Records() []Record {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make([]Record, 0, len(m.entries))
+	for addr, entry := range m.entries {
+		records = append(records, Record{Address: addr, Entry: entry})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		lhs, rhs := strings.ToLower(records[i].Name), strings.ToLower(records[j].Name)
+		if lhs != rhs {
+			return lhs < rhs
+		}
+
+		return records[i].Address.Hex() < records[j].Address.Hex()
+	})
+
+	return records
+}
+
+// Set adds or replaces the entry of the given address and saves the book.
+func (m *Book) Set(addr common.Address, entry Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[addr] = entry
+	return m.save()
+}
+
+// Remove forgets the given address and saves the book.
+func (m *Book) Remove(addr common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, addr)
+	return m.save()
+}
+
+func (m *Book) save() error {
+	content, err := yaml.Marshal(m.entries)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(m.path, content, 0600)
+}
@@ -2,6 +2,10 @@ package config
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mitchellh/go-homedir"
@@ -10,15 +14,81 @@ import (
 
 const (
 	DefaultConfigPath = "~/.sonm/icli.yaml"
+	DefaultNode       = "localhost:15030"
+	// DefaultIdleTimeout is the period without user input after which an
+	// unlocked session is locked.
+	DefaultIdleTimeout = 15 * time.Minute
 )
 
 type Config struct {
+	// Node is the address of the node gRPC endpoint to connect to.
+	Node string `yaml:"node"`
+	// AccountPaths maps known accounts to their keystore directories.
 	AccountPaths map[common.Address]string `yaml:"accounts"`
+	// AccountInfo holds optional metadata of accounts from AccountPaths. It
+	// is kept apart, so configs written by older versions remain valid.
+	AccountInfo map[common.Address]AccountInfo `yaml:"account_info,omitempty"`
+	Keymap      KeymapConfig                   `yaml:"keymap,omitempty"`
+	// Theme is either the name of the shipped theme, i.e. "dark", "light",
+	// "high-contrast" or "monochrome", or the path to a YAML theme file.
+	Theme         string              `yaml:"theme,omitempty"`
+	Notifications NotificationsConfig `yaml:"notifications,omitempty"`
+	Session       SessionConfig       `yaml:"session"`
+}
+
+// AccountInfo describes how an account is presented and used.
+type AccountInfo struct {
+	// Label is a human-readable name shown next to the address.
+	Label string `yaml:"label,omitempty"`
+	// Color marks the account in lists, specified like theme colors.
+	Color string `yaml:"color,omitempty"`
+	// Node overrides the node to connect to on behalf of the account.
+	Node string `yaml:"node,omitempty"`
+	// LastUsed is the time the account was unlocked last time.
+	LastUsed time.Time `yaml:"last_used,omitempty"`
+}
+
+// SessionConfig describes how unlocked accounts are protected.
+type SessionConfig struct {
+	// IdleTimeout locks the session after the given period without user
+	// input, for example "15m". Zero disables locking on idle.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+}
+
+// NotificationsConfig describes how notifications are delivered in addition
+// to toasts shown in the UI.
+//
+// Severities are "info", "success", "warn" or "error". Each hook is fired for
+// notifications at or above its severity and is disabled when the severity
+// is empty.
+type NotificationsConfig struct {
+	// Bell rings the terminal bell.
+	Bell string `yaml:"bell"`
+	// Desktop runs DesktopCommand to show a desktop notification.
+	Desktop string `yaml:"desktop"`
+	// DesktopCommand is the command receiving the title and the message as
+	// its last arguments, "notify-send" by default.
+	DesktopCommand string `yaml:"desktop_command"`
+}
+
+// KeymapConfig describes key bindings.
+type KeymapConfig struct {
+	// Preset is the name of the shipped keymap to start with, i.e. "default",
+	// "vim" or "emacs".
+	Preset string `yaml:"preset"`
+	// Bindings override chords of the preset, for example:
+	//   refresh: ["r", "Ctrl+R"]
+	Bindings map[string][]string `yaml:"bindings"`
 }
 
 func NewConfig() *Config {
 	return &Config{
+		Node:         DefaultNode,
 		AccountPaths: map[common.Address]string{},
+		AccountInfo:  map[common.Address]AccountInfo{},
+		Session: SessionConfig{
+			IdleTimeout: DefaultIdleTimeout,
+		},
 	}
 }
 
@@ -33,10 +103,121 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
-	cfg := &Config{}
+	cfg := NewConfig()
 	if err := yaml.Unmarshal(content, cfg); err != nil {
 		return nil, err
 	}
 
+	if cfg.AccountPaths == nil {
+		cfg.AccountPaths = map[common.Address]string{}
+	}
+	if cfg.AccountInfo == nil {
+		cfg.AccountInfo = map[common.Address]AccountInfo{}
+	}
+
 	return cfg, nil
 }
+
+// NodeOf returns the node to connect to on behalf of the given account.
+func (m *Config) NodeOf(account common.Address) string {
+	if node := m.AccountInfo[account].Node; node != "" {
+		return node
+	}
+
+	return m.Node
+}
+
+// RecentAccounts returns known accounts, the most recently used first.
+// Accounts that were never used follow in the order of their addresses.
+func (m *Config) RecentAccounts() []common.Address {
+	accounts := make([]common.Address, 0, len(m.AccountPaths))
+	for account := range m.AccountPaths {
+		accounts = append(accounts, account)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		lhs := m.AccountInfo[accounts[i]].LastUsed
+		rhs := m.AccountInfo[accounts[j]].LastUsed
+		if !lhs.Equal(rhs) {
+			return lhs.After(rhs)
+		}
+
+		return accounts[i].Hex() < accounts[j].Hex()
+	})
+
+	return accounts
+}
+
+// SaveConfig writes the config to the given path, creating parent
+// directories when required.
+func SaveConfig(path string, cfg *Config) error {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return err
+	}
+
+	content, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, content, 0600)
+}
+
+// AddAccount registers the account stored in the given keystore in the
+// config file at the given path, creating the file if it does not exist.
+//
+// The file is reloaded before, so overrides applied to the config in memory
+// are not saved.
+func AddAccount(path string, account common.Address, keystorePath string) error {
+	return updateFile(path, func(cfg *Config) {
+		cfg.AccountPaths[account] = keystorePath
+	})
+}
+
+// UpdateAccount applies the given function to the metadata of the account
+// both in the config in memory and in the config file at the given path.
+//
+// The file is reloaded before, like in AddAccount.
+func UpdateAccount(path string, cfg *Config, account common.Address, fn func(info *AccountInfo)) error {
+	info := cfg.AccountInfo[account]
+	fn(&info)
+	cfg.AccountInfo[account] = info
+
+	return updateFile(path, func(fileCfg *Config) {
+		info := fileCfg.AccountInfo[account]
+		fn(&info)
+		fileCfg.AccountInfo[account] = info
+	})
+}
+
+// RemoveAccount forgets the account both in the config in memory and in the
+// config file at the given path. The keystore is left intact.
+func RemoveAccount(path string, cfg *Config, account common.Address) error {
+	delete(cfg.AccountPaths, account)
+	delete(cfg.AccountInfo, account)
+
+	return updateFile(path, func(fileCfg *Config) {
+		delete(fileCfg.AccountPaths, account)
+		delete(fileCfg.AccountInfo, account)
+	})
+}
+
+func updateFile(path string, fn func(cfg *Config)) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		cfg = NewConfig()
+	}
+
+	fn(cfg)
+
+	return SaveConfig(path, cfg)
+}
@@ -1,97 +1,265 @@
 package interactions
 
 import (
+	"github.com/3Hren/sonmui/icli/internal/mp"
 	"github.com/marcusolsson/tui-go"
 )
 
-type FocusChain struct {
-	widgets []tui.Widget
+// FocusScope is a node of the focus graph. It holds widgets and nested scopes
+// in the traversal order, which is depth first.
+//
+// Disabled widgets and widgets of disabled scopes are skipped, so screens
+// enable and disable parts of a form as its state changes instead of
+// rebuilding the graph.
+type FocusScope struct {
+	nodes    []*focusNode
+	disabled bool
 }
 
-func NewFocusChain(widgets ...tui.Widget) *FocusChain {
-	return &FocusChain{
-		widgets: widgets,
+type focusNode struct {
+	widget   tui.Widget
+	scope    *FocusScope
+	disabled bool
+}
+
+func NewFocusScope(widgets ...tui.Widget) *FocusScope {
+	m := &FocusScope{}
+	for _, widget := range widgets {
+		m.AddWidget(widget)
 	}
+
+	return m
+}
+
+func (m *FocusScope) AddWidget(widget tui.Widget) {
+	m.nodes = append(m.nodes, &focusNode{widget: widget})
 }
 
-func (m *FocusChain) AddWidget(widget tui.Widget) {
-	m.widgets = append(m.widgets, widget)
+// InsertWidget inserts the widget at the given position of the scope.
+func (m *FocusScope) InsertWidget(id int, widget tui.Widget) {
+	m.nodes = append(m.nodes[:id], append([]*focusNode{{widget: widget}}, m.nodes[id:]...)...)
 }
 
-// FocusNext returns the widget in the ring that is after the given widget.
-func (m *FocusChain) FocusNext(current tui.Widget) tui.Widget {
-	for i, w := range m.widgets {
-		if w != current {
-			continue
+// AddScope appends the nested scope, whose widgets follow the ones already
+// added.
+func (m *FocusScope) AddScope(scope *FocusScope) {
+	m.nodes = append(m.nodes, &focusNode{scope: scope})
+}
+
+// RemoveWidget removes the widget from the scope or its nested scopes.
+func (m *FocusScope) RemoveWidget(widget tui.Widget) {
+	for id, node := range m.nodes {
+		if node.widget == widget {
+			m.nodes = append(m.nodes[:id], m.nodes[id+1:]...)
+			return
 		}
-		if i < len(m.widgets)-1 {
-			return m.widgets[i+1]
+		if node.scope != nil {
+			node.scope.RemoveWidget(widget)
 		}
-		return m.widgets[0]
 	}
-	return nil
 }
 
-// FocusPrev returns the widget in the ring that is before the given widget.
-func (m *FocusChain) FocusPrev(current tui.Widget) tui.Widget {
-	for i, w := range m.widgets {
-		if w != current {
-			continue
+// SetEnabled enables or disables the whole scope.
+func (m *FocusScope) SetEnabled(enabled bool) {
+	m.disabled = !enabled
+}
+
+// SetWidgetEnabled enables or disables the widget found in the scope or its
+// nested scopes.
+func (m *FocusScope) SetWidgetEnabled(widget tui.Widget, enabled bool) {
+	if node := m.find(widget); node != nil {
+		node.disabled = !enabled
+	}
+}
+
+// IsEnabled checks whether the widget can receive the focus, i.e. it is
+// present and neither it nor any of its scopes is disabled.
+func (m *FocusScope) IsEnabled(widget tui.Widget) bool {
+	for _, w := range m.Widgets() {
+		if w == widget {
+			return true
 		}
-		if i <= 0 {
-			return m.widgets[len(m.widgets)-1]
+	}
+
+	return false
+}
+
+func (m *FocusScope) find(widget tui.Widget) *focusNode {
+	for _, node := range m.nodes {
+		if node.widget == widget {
+			return node
+		}
+		if node.scope != nil {
+			if found := node.scope.find(widget); found != nil {
+				return found
+			}
 		}
-		return m.widgets[i-1]
 	}
+
 	return nil
 }
 
+// Widgets returns enabled widgets in the traversal order.
+func (m *FocusScope) Widgets() []tui.Widget {
+	if m.disabled {
+		return nil
+	}
+
+	var widgets []tui.Widget
+	for _, node := range m.nodes {
+		switch {
+		case node.disabled:
+		case node.scope != nil:
+			widgets = append(widgets, node.scope.Widgets()...)
+		default:
+			widgets = append(widgets, node.widget)
+		}
+	}
+
+	return widgets
+}
+
+// FocusNext returns the enabled widget that is after the given widget,
+// wrapping around. The default widget is returned when the given one is
+// not enabled.
+func (m *FocusScope) FocusNext(current tui.Widget) tui.Widget {
+	return m.step(current, 1)
+}
+
+// FocusPrev returns the enabled widget that is before the given widget,
+// wrapping around. The default widget is returned when the given one is
+// not enabled.
+func (m *FocusScope) FocusPrev(current tui.Widget) tui.Widget {
+	return m.step(current, -1)
+}
+
+func (m *FocusScope) step(current tui.Widget, delta int) tui.Widget {
+	widgets := m.Widgets()
+	for id, widget := range widgets {
+		if widget == current {
+			return widgets[(id+delta+len(widgets))%len(widgets)]
+		}
+	}
+
+	return m.FocusDefault()
+}
+
 // FocusDefault returns the default widget for when there is no widget
 // currently focused.
-func (m *FocusChain) FocusDefault() tui.Widget {
-	if len(m.widgets) == 0 {
+func (m *FocusScope) FocusDefault() tui.Widget {
+	widgets := m.Widgets()
+	if len(widgets) == 0 {
 		return nil
 	}
-	return m.widgets[0]
+
+	return widgets[0]
 }
 
+// FocusChange is emitted through FocusController.OnFocusChanged. Either of
+// widgets may be nil.
+type FocusChange struct {
+	From tui.Widget
+	To   tui.Widget
+	// Serial increases with each change. Changes are delivered through the
+	// router, so listeners compare it with FocusController.Serial to drop
+	// changes made before some point.
+	Serial uint64
+}
+
+// FocusController moves the focus through the graph of widgets rooted at the
+// given scope.
 type FocusController struct {
 	FocusedWidget tui.Widget
 
-	focusChain *FocusChain
+	scope  *FocusScope
+	root   tui.Widget
+	serial uint64
+
+	OnFocusChanged *mp.Signal
 }
 
-func NewFocusController(focusChain *FocusChain) *FocusController {
+func NewFocusController(scope *FocusScope, router *mp.Router) *FocusController {
 	return &FocusController{
-		FocusedWidget: focusChain.FocusDefault(),
+		FocusedWidget: scope.FocusDefault(),
 
-		focusChain: focusChain,
+		scope: scope,
+
+		OnFocusChanged: router.NewSignal(),
 	}
 }
 
-func (m *FocusController) FocusDefaultWidget() {
-	if m.FocusedWidget != nil {
-		m.FocusedWidget.SetFocused(false)
-	}
+// Scope returns the root scope of the graph.
+func (m *FocusController) Scope() *FocusScope {
+	return m.scope
+}
 
-	m.FocusedWidget = m.focusChain.FocusDefault()
-	m.FocusedWidget.SetFocused(true)
+// SetRoot sets the widget containing all widgets of the graph. It is drawn
+// off-screen to find positions of anchored widgets for spatial navigation.
+func (m *FocusController) SetRoot(root tui.Widget) {
+	m.root = root
 }
 
-func (m *FocusController) FocusNextWidget() {
-	if m.FocusedWidget != nil {
-		m.FocusedWidget.SetFocused(false)
+// Focus moves the focus to the given widget. Nil only removes the focus.
+func (m *FocusController) Focus(widget tui.Widget) {
+	prev := m.FocusedWidget
+	if prev != nil {
+		prev.SetFocused(false)
+	}
+
+	m.FocusedWidget = widget
+	if widget != nil {
+		widget.SetFocused(true)
+	}
+
+	if prev != widget {
+		m.serial++
+		m.OnFocusChanged.Emit(FocusChange{From: prev, To: widget, Serial: m.serial})
 	}
+}
 
-	m.FocusedWidget = m.focusChain.FocusNext(m.FocusedWidget)
-	m.FocusedWidget.SetFocused(true)
+// Serial returns the serial of the last focus change.
+func (m *FocusController) Serial() uint64 {
+	return m.serial
+}
+
+func (m *FocusController) FocusDefaultWidget() {
+	m.Focus(m.scope.FocusDefault())
+}
+
+func (m *FocusController) FocusNextWidget() {
+	m.Focus(m.scope.FocusNext(m.FocusedWidget))
 }
 
 func (m *FocusController) FocusPrevWidget() {
-	if m.FocusedWidget != nil {
-		m.FocusedWidget.SetFocused(false)
+	m.Focus(m.scope.FocusPrev(m.FocusedWidget))
+}
+
+// FocusDirection moves the focus to the nearest enabled widget in the given
+// direction, returning false if there is none.
+func (m *FocusController) FocusDirection(direction Direction) bool {
+	if m.root == nil || m.FocusedWidget == nil {
+		return false
+	}
+
+	bounds := Locate(m.root)
+
+	current, ok := bounds[m.FocusedWidget]
+	if !ok {
+		return false
+	}
+
+	var candidates []tui.Widget
+	for _, widget := range m.scope.Widgets() {
+		if widget != m.FocusedWidget {
+			candidates = append(candidates, widget)
+		}
+	}
+
+	next := nearest(current, direction, bounds, candidates)
+	if next == nil {
+		return false
 	}
 
-	m.FocusedWidget = m.focusChain.FocusPrev(m.FocusedWidget)
-	m.FocusedWidget.SetFocused(true)
+	m.Focus(next)
+	return true
 }
@@ -0,0 +1,143 @@
+package interactions
+
+import (
+	"image"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Direction is a direction of spatial focus navigation.
+type Direction int
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+// probes maps off-screen painters used by Locate to their surfaces. Both are
+// only touched from the UI thread.
+var probes = map[*tui.Painter]*probeSurface{}
+
+// Anchor wraps a widget, so its bounds can be found by Locate.
+//
+// tui-go doesn't expose positions of widgets, only their sizes, so bounds
+// are found by drawing the tree off-screen, where anchors report the origin
+// of the wrapped widget by placing the cursor.
+type Anchor struct {
+	tui.Widget
+}
+
+func NewAnchor(widget tui.Widget) *Anchor {
+	return &Anchor{Widget: widget}
+}
+
+func (m *Anchor) Draw(painter *tui.Painter) {
+	if probe, ok := probes[painter]; ok {
+		probe.current = m.Widget
+		painter.DrawCursor(0, 0)
+	}
+
+	m.Widget.Draw(painter)
+}
+
+// Locate returns bounds of anchored widgets found in the tree relative to
+// the root widget. The root must have been laid out, i.e. drawn at least
+// once.
+func Locate(root tui.Widget) map[tui.Widget]image.Rectangle {
+	probe := &probeSurface{
+		size:   root.Size(),
+		bounds: map[tui.Widget]image.Rectangle{},
+	}
+
+	painter := tui.NewPainter(probe, tui.NewTheme())
+	probes[painter] = probe
+	defer delete(probes, painter)
+
+	root.Draw(painter)
+
+	return probe.bounds
+}
+
+// probeSurface discards everything drawn, recording cursor positions set by
+// anchors instead.
+type probeSurface struct {
+	size    image.Point
+	current tui.Widget
+	bounds  map[tui.Widget]image.Rectangle
+}
+
+func (m *probeSurface) SetCell(x, y int, ch rune, s tui.Style) {}
+
+func (m *probeSurface) SetCursor(x, y int) {
+	if m.current == nil {
+		return
+	}
+
+	origin := image.Pt(x, y)
+	m.bounds[m.current] = image.Rectangle{Min: origin, Max: origin.Add(m.current.Size())}
+	m.current = nil
+}
+
+func (m *probeSurface) HideCursor() {}
+
+func (m *probeSurface) Begin() {}
+
+func (m *probeSurface) End() {}
+
+func (m *probeSurface) Size() image.Point {
+	return m.size
+}
+
+// nearest returns the candidate closest to the given rectangle in the
+// direction. Only candidates lying entirely on that side are considered,
+// ranked by the distance along the direction plus twice the misalignment
+// across it, so that aligned widgets win over closer but diagonal ones. Ties are broken
+// by the order of candidates.
+func nearest(current image.Rectangle, direction Direction, bounds map[tui.Widget]image.Rectangle, candidates []tui.Widget) tui.Widget {
+	var best tui.Widget
+	bestScore := 0
+
+	for _, widget := range candidates {
+		rect, ok := bounds[widget]
+		if !ok || rect.Empty() {
+			continue
+		}
+
+		var gap, offset int
+		switch direction {
+		case Left:
+			gap, offset = current.Min.X-rect.Max.X, misalignment(current.Min.Y, current.Max.Y, rect.Min.Y, rect.Max.Y)
+		case Right:
+			gap, offset = rect.Min.X-current.Max.X, misalignment(current.Min.Y, current.Max.Y, rect.Min.Y, rect.Max.Y)
+		case Up:
+			gap, offset = current.Min.Y-rect.Max.Y, misalignment(current.Min.X, current.Max.X, rect.Min.X, rect.Max.X)
+		case Down:
+			gap, offset = rect.Min.Y-current.Max.Y, misalignment(current.Min.X, current.Max.X, rect.Min.X, rect.Max.X)
+		}
+
+		if gap < 0 {
+			continue
+		}
+
+		if score := gap + 2*offset; best == nil || score < bestScore {
+			best, bestScore = widget, score
+		}
+	}
+
+	return best
+}
+
+// misalignment returns the distance between two ranges, which is zero when
+// they overlap.
+func misalignment(min1, max1, min2, max2 int) int {
+	switch {
+	case max2 <= min1:
+		return min1 - max2 + 1
+	case max1 <= min2:
+		return min2 - max1 + 1
+	default:
+		return 0
+	}
+}
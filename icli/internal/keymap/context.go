@@ -0,0 +1,91 @@
+package keymap
+
+import (
+	"github.com/marcusolsson/tui-go"
+)
+
+// Binding describes an action available in the current context.
+type Binding struct {
+	Action Action
+	// Description explains what the action does in the context. The default
+	// action description is used when empty.
+	Description string
+}
+
+type contextEntry struct {
+	widget  tui.Widget
+	binding Binding
+}
+
+// Context collects actions handled by a controller, allowing to generate
+// help for the screen it controls.
+//
+// Actions may be registered for a particular widget, in which case they are
+// available only while the widget is focused.
+type Context struct {
+	entries []*contextEntry
+	inputs  []tui.Widget
+}
+
+func NewContext() *Context {
+	return &Context{}
+}
+
+// Register adds an action available on the whole screen.
+func (m *Context) Register(action Action, description string) {
+	m.RegisterFor(nil, action, description)
+}
+
+// RegisterFor adds an action available only while the given widget is
+// focused.
+func (m *Context) RegisterFor(widget tui.Widget, action Action, description string) {
+	if description == "" {
+		description = Describe(action)
+	}
+
+	m.entries = append(m.entries, &contextEntry{
+		widget:  widget,
+		binding: Binding{Action: action, Description: description},
+	})
+}
+
+// RegisterInput marks the given widget as accepting text, which means that
+// rune chords must not be intercepted while it is focused.
+func (m *Context) RegisterInput(widget tui.Widget) {
+	m.inputs = append(m.inputs, widget)
+}
+
+// InputFocused checks whether one of text inputs is focused.
+func (m *Context) InputFocused() bool {
+	if m == nil {
+		return false
+	}
+
+	for _, widget := range m.inputs {
+		if widget.IsFocused() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Bindings returns actions available right now. Actions of the focused
+// widget come first, followed by screen-wide ones.
+func (m *Context) Bindings() []Binding {
+	if m == nil {
+		return nil
+	}
+
+	var focused, screen []Binding
+	for _, entry := range m.entries {
+		switch {
+		case entry.widget == nil:
+			screen = append(screen, entry.binding)
+		case entry.widget.IsFocused():
+			focused = append(focused, entry.binding)
+		}
+	}
+
+	return append(focused, screen...)
+}
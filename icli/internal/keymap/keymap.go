@@ -0,0 +1,259 @@
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/3Hren/sonmui/icli/internal/config"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	DefaultPreset = "default"
+)
+
+// Action is a named user action that can be bound to one or more key
+// chords.
+type Action string
+
+const (
+	ConfirmWorker Action = "confirm-worker"
+	Refresh       Action = "refresh"
+	Back          Action = "back"
+	NextFocus     Action = "next-focus"
+	PrevFocus     Action = "prev-focus"
+	FocusLeft     Action = "focus-left"
+	FocusRight    Action = "focus-right"
+	FocusUp       Action = "focus-up"
+	FocusDown     Action = "focus-down"
+	Complete      Action = "complete"
+	Increment     Action = "increment"
+	Decrement     Action = "decrement"
+	Quit          Action = "quit"
+	Search        Action = "search"
+	Help          Action = "help"
+	ToggleLog     Action = "toggle-log"
+	Notifications Action = "notifications"
+	Lock          Action = "lock"
+	NextTab       Action = "next-tab"
+	Rename        Action = "rename"
+	Remove        Action = "remove"
+)
+
+var descriptions = map[Action]string{
+	ConfirmWorker: "Confirm the selected worker",
+	Refresh:       "Refresh the current view",
+	Back:          "Go back to the previous screen",
+	NextFocus:     "Focus the next widget",
+	PrevFocus:     "Focus the previous widget",
+	FocusLeft:     "Focus the panel on the left",
+	FocusRight:    "Focus the panel on the right",
+	FocusUp:       "Focus the widget above",
+	FocusDown:     "Focus the widget below",
+	Complete:      "Show completion hints",
+	Increment:     "Increase the value",
+	Decrement:     "Decrease the value",
+	Quit:          "Quit",
+	Search:        "Search",
+	Help:          "Show available actions",
+	ToggleLog:     "Show or hide the log console",
+	Notifications: "Show notification history",
+	Lock:          "Lock all sessions",
+	NextTab:       "Switch to the next tab",
+	Rename:        "Rename the selected item",
+	Remove:        "Remove the selected item",
+}
+
+var presets = map[string]map[Action][]string{
+	"default": {
+		ConfirmWorker: {"c"},
+		Refresh:       {"r", "F5"},
+		Back:          {"Esc"},
+		NextFocus:     {"Tab"},
+		PrevFocus:     {"Backtab", "Shift+Backtab"},
+		FocusLeft:     {"Left"},
+		FocusRight:    {"Right"},
+		FocusUp:       {"Alt+Up"},
+		FocusDown:     {"Alt+Down"},
+		Complete:      {"Tab"},
+		Increment:     {"Up"},
+		Decrement:     {"Down"},
+		Quit:          {"Ctrl+C"},
+		Search:        {"/"},
+		Help:          {"F1", "?"},
+		ToggleLog:     {"F2"},
+		Notifications: {"F3"},
+		Lock:          {"F12"},
+		NextTab:       {"F4"},
+		Rename:        {"e"},
+		Remove:        {"Delete"},
+	},
+	"vim": {
+		ConfirmWorker: {"c"},
+		Refresh:       {"r", "Ctrl+L"},
+		Back:          {"Esc"},
+		NextFocus:     {"Tab"},
+		PrevFocus:     {"Backtab", "Shift+Backtab"},
+		FocusLeft:     {"Left", "h"},
+		FocusRight:    {"Right", "l"},
+		FocusUp:       {"Alt+Up"},
+		FocusDown:     {"Alt+Down"},
+		Complete:      {"Tab"},
+		Increment:     {"Up"},
+		Decrement:     {"Down"},
+		Quit:          {"Ctrl+C"},
+		Search:        {"/"},
+		Help:          {"F1", "?"},
+		ToggleLog:     {"F2"},
+		Notifications: {"F3"},
+		Lock:          {"F12"},
+		NextTab:       {"F4", "Ctrl+N"},
+		Rename:        {"e"},
+		Remove:        {"d", "Delete"},
+	},
+	"emacs": {
+		ConfirmWorker: {"c"},
+		Refresh:       {"g", "F5"},
+		Back:          {"Esc", "Ctrl+G"},
+		NextFocus:     {"Tab"},
+		PrevFocus:     {"Backtab", "Shift+Backtab"},
+		FocusLeft:     {"Left", "Ctrl+B"},
+		FocusRight:    {"Right", "Ctrl+F"},
+		FocusUp:       {"Alt+Up"},
+		FocusDown:     {"Alt+Down"},
+		Complete:      {"Tab"},
+		Increment:     {"Up"},
+		Decrement:     {"Down"},
+		Quit:          {"Ctrl+C"},
+		Search:        {"Ctrl+S"},
+		Help:          {"F1", "?"},
+		ToggleLog:     {"F2"},
+		Notifications: {"F3"},
+		Lock:          {"F12"},
+		NextTab:       {"F4"},
+		Rename:        {"e"},
+		Remove:        {"Delete", "Ctrl+D"},
+	},
+}
+
+var defaultKeymap = mustPreset(DefaultPreset)
+
+// Keymap maps named actions to key chords.
+//
+// Chords are specified using tui-go key names, for example "c", "Ctrl+R",
+// "Tab", "Esc" or "F5". Named keys and modifiers are matched
+// case-insensitively, while runes are case-sensitive.
+//
+// A nil keymap behaves like the default preset.
+type Keymap struct {
+	bindings map[Action][]string
+}
+
+// NewKeymap constructs a keymap using the preset from the given config with
+// its overrides applied on top.
+func NewKeymap(cfg config.KeymapConfig) (*Keymap, error) {
+	preset := cfg.Preset
+	if preset == "" {
+		preset = DefaultPreset
+	}
+
+	m, err := NewPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, chords := range cfg.Bindings {
+		action := Action(name)
+		if _, ok := descriptions[action]; !ok {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+
+		m.Bind(action, chords...)
+	}
+
+	return m, nil
+}
+
+// NewPreset constructs a keymap from the preset with the given name.
+func NewPreset(name string) (*Keymap, error) {
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown keymap preset %q, expected one of: %s", name, strings.Join(Presets(), ", "))
+	}
+
+	m := &Keymap{
+		bindings: map[Action][]string{},
+	}
+	for action, chords := range preset {
+		m.Bind(action, chords...)
+	}
+
+	return m, nil
+}
+
+func mustPreset(name string) *Keymap {
+	m, err := NewPreset(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Presets returns names of all shipped presets.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Describe returns a human-readable description of the given action.
+func Describe(action Action) string {
+	return descriptions[action]
+}
+
+// Bind replaces chords bound to the given action.
+func (m *Keymap) Bind(action Action, chords ...string) {
+	m.bindings[action] = append([]string(nil), chords...)
+}
+
+// Chords returns key chords bound to the given action.
+func (m *Keymap) Chords(action Action) []string {
+	if m == nil {
+		m = defaultKeymap
+	}
+
+	return m.bindings[action]
+}
+
+// Match checks whether the given key event triggers the action.
+func (m *Keymap) Match(action Action, ev tui.KeyEvent) bool {
+	if m == nil {
+		m = defaultKeymap
+	}
+
+	name := normalize(ev.Name())
+
+	for _, chord := range m.bindings[action] {
+		chord = normalize(chord)
+
+		if ev.Key == tui.KeyRune && ev.Modifiers == tui.ModNone {
+			if chord == name {
+				return true
+			}
+		} else if strings.EqualFold(chord, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalize(chord string) string {
+	return strings.Replace(chord, "Ctrl-", "Ctrl+", 1)
+}
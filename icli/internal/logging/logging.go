@@ -0,0 +1,225 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultLogPath = "~/.sonm/icli.log"
+	// DefaultHistorySize is the number of recent entries kept in memory for
+	// the log console.
+	DefaultHistorySize = 256
+
+	timeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "DEBUG",
+	InfoLevel:  "INFO",
+	WarnLevel:  "WARN",
+	ErrorLevel: "ERROR",
+}
+
+func (m Level) String() string {
+	if name, ok := levelNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int(m))
+}
+
+// ParseLevel parses level name in any case, for example "info" or "WARN".
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown log level %q, expected one of: debug, info, warn, error", name)
+}
+
+// Field is a key-value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err constructs the conventional field for the given error.
+func Err(err error) Field {
+	return F("error", err)
+}
+
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// String formats the entry as a single logfmt-like line without the
+// trailing newline.
+func (m *Entry) String() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%s %-5s %s", m.Time.Format(timeFormat), m.Level, m.Message)
+	m.writeFields(buf)
+
+	return buf.String()
+}
+
+// Text formats the entry message with its fields, but without the timestamp
+// and level.
+func (m *Entry) Text() string {
+	buf := &bytes.Buffer{}
+	buf.WriteString(m.Message)
+	m.writeFields(buf)
+
+	return buf.String()
+}
+
+func (m *Entry) writeFields(buf *bytes.Buffer) {
+	for _, field := range m.Fields {
+		value := fmt.Sprintf("%v", field.Value)
+		if value == "" || strings.ContainsAny(value, " \t\"=") {
+			value = strconv.Quote(value)
+		}
+
+		fmt.Fprintf(buf, " %s=%s", field.Key, value)
+	}
+}
+
+// Logger writes structured entries into the underlying writer, keeping the
+// most recent ones in memory so they can be shown in the UI.
+//
+// Entries below the configured level are dropped. The logger is safe for
+// concurrent use, but subscribers are notified from the logging goroutine, so
+// UI code must route updates to the UI thread itself.
+//
+// A nil logger discards everything.
+type Logger struct {
+	mu          sync.Mutex
+	wr          io.Writer
+	level       Level
+	history     []Entry
+	historySize int
+	subscribers []func(entry Entry)
+}
+
+// NewLogger constructs a logger writing to the given writer, which may be nil
+// if entries should be kept in memory only.
+func NewLogger(wr io.Writer, level Level) *Logger {
+	return &Logger{
+		wr:          wr,
+		level:       level,
+		historySize: DefaultHistorySize,
+	}
+}
+
+// Subscribe registers the given function to be called for each new entry.
+func (m *Logger) Subscribe(fn func(entry Entry)) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Recent returns a copy of the most recent entries, oldest first.
+func (m *Logger) Recent() []Entry {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Entry(nil), m.history...)
+}
+
+func (m *Logger) Debug(message string, fields ...Field) {
+	m.Log(DebugLevel, message, fields...)
+}
+
+func (m *Logger) Info(message string, fields ...Field) {
+	m.Log(InfoLevel, message, fields...)
+}
+
+func (m *Logger) Warn(message string, fields ...Field) {
+	m.Log(WarnLevel, message, fields...)
+}
+
+func (m *Logger) Error(message string, fields ...Field) {
+	m.Log(ErrorLevel, message, fields...)
+}
+
+func (m *Logger) Log(level Level, message string, fields ...Field) {
+	if m == nil || level < m.level {
+		return
+	}
+
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  fields,
+	}
+
+	m.mu.Lock()
+	if m.wr != nil {
+		// There is nowhere to report logging failures to.
+		io.WriteString(m.wr, entry.String()+"\n")
+	}
+
+	m.history = append(m.history, entry)
+	if len(m.history) > m.historySize {
+		m.history = m.history[len(m.history)-m.historySize:]
+	}
+
+	subscribers := m.subscribers
+	m.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(entry)
+	}
+}
+
+// Writer returns a writer logging each written line as an entry with the
+// given level, which allows to redirect standard loggers.
+func (m *Logger) Writer(level Level) io.Writer {
+	return &lineWriter{log: m, level: level}
+}
+
+type lineWriter struct {
+	log   *Logger
+	level Level
+}
+
+func (m *lineWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		m.log.Log(m.level, line)
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024
+	DefaultMaxBackups = 3
+)
+
+// RotatingFile is a log file that is rotated when it grows beyond the
+// maximum size.
+//
+// Rotated files are named by appending a sequence number to the file name,
+// i.e. "icli.log.1" is the most recent backup. Backups beyond the limit are
+// removed.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile opens the log file at the given path for appending,
+// creating it together with its parent directories when required.
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	m := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := m.open(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *RotatingFile) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if m.size > 0 && m.size+int64(len(p)) > m.maxSize {
+		if err := m.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := m.file.Write(p)
+	m.size += int64(n)
+
+	return n, err
+}
+
+func (m *RotatingFile) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.file == nil {
+		return nil
+	}
+
+	err := m.file.Close()
+	m.file = nil
+
+	return err
+}
+
+func (m *RotatingFile) open() error {
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	m.file = file
+	m.size = info.Size()
+
+	return nil
+}
+
+func (m *RotatingFile) rotate() error {
+	if err := m.file.Close(); err != nil {
+		return err
+	}
+	m.file = nil
+
+	if m.maxBackups > 0 {
+		os.Remove(m.backupPath(m.maxBackups))
+		for id := m.maxBackups - 1; id > 0; id-- {
+			os.Rename(m.backupPath(id), m.backupPath(id+1))
+		}
+
+		if err := os.Rename(m.path, m.backupPath(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(m.path); err != nil {
+		return err
+	}
+
+	return m.open()
+}
+
+func (m *RotatingFile) backupPath(id int) string {
+	return fmt.Sprintf("%s.%d", m.path, id)
+}
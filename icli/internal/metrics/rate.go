@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Rate converts samples of a cumulative counter, like the CPU time consumed
+// by tasks, into the rate of its growth per second.
+//
+// It is safe to add samples from multiple goroutines.
+type Rate struct {
+	mu        sync.Mutex
+	value     float64
+	sampledAt time.Time
+}
+
+func NewRate() *Rate {
+	return &Rate{}
+}
+
+// Add samples the counter and returns its rate since the previous sample.
+//
+// There is no rate for the first sample, nor when the counter decreases,
+// for example because tasks contributing to it have finished.
+func (m *Rate) Add(value float64, sampledAt time.Time) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prevValue, prevSampledAt := m.value, m.sampledAt
+	m.value, m.sampledAt = value, sampledAt
+
+	elapsed := sampledAt.Sub(prevSampledAt).Seconds()
+	if prevSampledAt.IsZero() || elapsed <= 0 || value < prevValue {
+		return 0, false
+	}
+
+	return (value - prevValue) / elapsed, true
+}
+
+// Reset forgets the previous sample.
+func (m *Rate) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.value, m.sampledAt = 0, time.Time{}
+}
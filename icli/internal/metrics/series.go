@@ -0,0 +1,67 @@
+// Package metrics keeps history of values polled from the node, like the
+// balance, the number of workers or resource usage of tasks, to show their
+// trends on charts.
+package metrics
+
+import (
+	"sync"
+)
+
+const (
+	DefaultHistorySize = 120
+)
+
+// Series is a time series of values sampled at regular intervals. Only the
+// given number of the most recent values is kept.
+//
+// It is safe to add values while charts read them from the UI thread.
+type Series struct {
+	mu       sync.Mutex
+	capacity int
+	values   []float64
+}
+
+func NewSeries(capacity int) *Series {
+	return &Series{
+		capacity: capacity,
+	}
+}
+
+// Add appends the value, dropping the oldest one if the series is full.
+func (m *Series) Add(value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values = append(m.values, value)
+	if len(m.values) > m.capacity {
+		m.values = append(m.values[:0], m.values[len(m.values)-m.capacity:]...)
+	}
+}
+
+// Values returns a copy of values from the oldest to the most recent one.
+func (m *Series) Values() []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]float64(nil), m.values...)
+}
+
+// Last returns the most recent value, if any.
+func (m *Series) Last() (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.values) == 0 {
+		return 0, false
+	}
+
+	return m.values[len(m.values)-1], true
+}
+
+// Reset forgets all values.
+func (m *Series) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values = nil
+}
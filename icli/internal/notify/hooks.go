@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// DefaultDesktopCommand is the command used to show desktop notifications
+	// on most Linux desktops.
+	DefaultDesktopCommand = "notify-send"
+)
+
+// NewBellHook constructs a hook ringing the terminal bell by writing BEL into
+// the given writer for notifications at or above the given severity.
+func NewBellHook(wr io.Writer, minSeverity Severity) Hook {
+	return func(notification Notification) {
+		if notification.Severity >= minSeverity {
+			wr.Write([]byte{'\a'})
+		}
+	}
+}
+
+// NewDesktopHook constructs a hook that runs the given command for
+// notifications at or above the given severity, passing the title and the
+// notification message as the last two arguments, like notify-send expects.
+//
+// The command is split by spaces, so it may contain additional arguments,
+// for example "notify-send -a icli". It is started asynchronously and its
+// failures are ignored.
+func NewDesktopHook(command string, minSeverity Severity) Hook {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		args = []string{DefaultDesktopCommand}
+	}
+
+	return func(notification Notification) {
+		if notification.Severity < minSeverity {
+			return
+		}
+
+		cmdArgs := append(append([]string(nil), args[1:]...), "icli: "+notification.Severity.String(), notification.Message)
+		cmd := exec.Command(args[0], cmdArgs...)
+		if err := cmd.Start(); err != nil {
+			return
+		}
+
+		go cmd.Wait()
+	}
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/mp"
+)
+
+const (
+	DefaultHistorySize = 100
+)
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Success
+	Warning
+	Error
+)
+
+var severityNames = map[Severity]string{
+	Info:    "info",
+	Success: "success",
+	Warning: "warn",
+	Error:   "error",
+}
+
+// String returns the severity name, which is also used as the suffix of
+// theme styles, for example "toast.warn".
+func (m Severity) String() string {
+	if name, ok := severityNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("severity(%d)", int(m))
+}
+
+// ParseSeverity parses severity name as returned by Severity.String.
+func ParseSeverity(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if name == severityName {
+			return severity, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown severity %q, expected one of: info, success, warn, error", name)
+}
+
+type Notification struct {
+	Time     time.Time
+	Severity Severity
+	Message  string
+}
+
+// Hook is called for every posted notification on the posting goroutine, so
+// it must not block.
+type Hook func(notification Notification)
+
+// Center collects notifications about results of asynchronous operations,
+// keeping the most recent ones as history.
+//
+// Notifications may be posted from any goroutine. Subscribers connected to
+// OnPosted are notified on the UI thread.
+type Center struct {
+	mu          sync.Mutex
+	history     []Notification
+	historySize int
+	hooks       []Hook
+
+	// OnPosted is emitted with Notification for every posted notification.
+	OnPosted *mp.Signal
+}
+
+func NewCenter(router *mp.Router) *Center {
+	return &Center{
+		historySize: DefaultHistorySize,
+		OnPosted:    router.NewSignal(),
+	}
+}
+
+// AddHook registers the given hook, which is called for every notification
+// posted afterwards.
+func (m *Center) AddHook(hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, hook)
+}
+
+// History returns a copy of recent notifications, oldest first.
+func (m *Center) History() []Notification {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Notification(nil), m.history...)
+}
+
+func (m *Center) Info(format string, args ...interface{}) {
+	m.Post(Info, fmt.Sprintf(format, args...))
+}
+
+func (m *Center) Success(format string, args ...interface{}) {
+	m.Post(Success, fmt.Sprintf(format, args...))
+}
+
+func (m *Center) Warn(format string, args ...interface{}) {
+	m.Post(Warning, fmt.Sprintf(format, args...))
+}
+
+func (m *Center) Error(format string, args ...interface{}) {
+	m.Post(Error, fmt.Sprintf(format, args...))
+}
+
+func (m *Center) Post(severity Severity, message string) {
+	notification := Notification{
+		Time:     time.Now(),
+		Severity: severity,
+		Message:  message,
+	}
+
+	m.mu.Lock()
+	m.history = append(m.history, notification)
+	if len(m.history) > m.historySize {
+		m.history = m.history[len(m.history)-m.historySize:]
+	}
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(notification)
+	}
+
+	m.OnPosted.Emit(notification)
+}
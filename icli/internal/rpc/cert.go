@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/signer"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// certificateLifetime is the lifetime of certificates signed by signers
+	// not holding the key in memory. External signers may ask the user to
+	// approve each signature, so certificates live long.
+	certificateLifetime = 24 * time.Hour
+	// certificateRenewal is how long before expiration certificates are
+	// renewed.
+	certificateRenewal = time.Hour
+)
+
+// certificateSigner issues TLS certificates on behalf of the signer account
+// the same way the core library does with the key in memory: the public part
+// of a freshly generated certificate key is signed with the account key and
+// the signature goes into the common name, so the node recovers the account
+// from it.
+//
+// Certificates are issued on demand and renewed before they expire, so the
+// signer is asked for a signature only when a handshake needs a new one.
+type certificateSigner struct {
+	mu     sync.Mutex
+	signer signer.Signer
+	cert   *tls.Certificate
+}
+
+func newCertificateSigner(s signer.Signer) *certificateSigner {
+	return &certificateSigner{
+		signer: s,
+	}
+}
+
+// TLSConfig returns the client config presenting certificates of the signer.
+//
+// The chain of the node is not verified here, since it is self-signed. The
+// core credentials check the account the node certificate is signed with
+// instead.
+func (m *certificateSigner) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: m.GetClientCertificate,
+		InsecureSkipVerify:   true,
+	}
+}
+
+func (m *certificateSigner) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cert == nil || time.Now().Add(certificateRenewal).After(m.cert.Leaf.NotAfter) {
+		cert, err := newCertificate(m.signer, certificateLifetime)
+		if err != nil {
+			return nil, err
+		}
+
+		m.cert = cert
+	}
+
+	return m.cert, nil
+}
+
+func newCertificate(s signer.Signer, lifetime time.Duration) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.SignHash(crypto.Keccak256(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign TLS certificate: %v", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: hex.EncodeToString(signature)},
+		NotBefore:             now,
+		NotAfter:              now.Add(lifetime),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
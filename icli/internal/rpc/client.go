@@ -0,0 +1,179 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sonm-io/core/proto"
+	"google.golang.org/grpc"
+)
+
+// Client wraps node services used by icli, performing requests on behalf of
+// a single account.
+type Client struct {
+	conn    *grpc.ClientConn
+	account common.Address
+	log     *logging.Logger
+
+	master sonm.MasterManagementClient
+	token  sonm.TokenManagementClient
+	market sonm.MarketClient
+	dwh    sonm.DWHClient
+	tasks  sonm.TaskManagementClient
+}
+
+// NewClient constructs a client, which logs every call into the given
+// logger. The logger may be nil.
+func NewClient(conn *grpc.ClientConn, account common.Address, log *logging.Logger) *Client {
+	return &Client{
+		conn:    conn,
+		account: account,
+		log:     log,
+		master:  sonm.NewMasterManagementClient(conn),
+		token:   sonm.NewTokenManagementClient(conn),
+		market:  sonm.NewMarketClient(conn),
+		dwh:     sonm.NewDWHClient(conn),
+		tasks:   sonm.NewTaskManagementClient(conn),
+	}
+}
+
+// Target returns the node address the client is connected to.
+func (m *Client) Target() string {
+	return m.conn.Target()
+}
+
+// Account returns the address of the account the client acts on behalf of.
+func (m *Client) Account() common.Address {
+	return m.account
+}
+
+// Close closes the underlying connection.
+func (m *Client) Close() error {
+	return m.conn.Close()
+}
+
+// Workers returns workers registered for the account.
+func (m *Client) Workers(ctx context.Context) ([]*sonm.DWHWorker, error) {
+	startedAt := time.Now()
+	reply, err := m.master.WorkersList(ctx, sonm.NewEthAddress(m.account))
+	m.trace("WorkersList", startedAt, reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.GetWorkers(), nil
+}
+
+// ConfirmWorker confirms the worker with the given address as the account's
+// slave.
+func (m *Client) ConfirmWorker(ctx context.Context, worker common.Address) error {
+	startedAt := time.Now()
+	_, err := m.master.WorkerConfirm(ctx, sonm.NewEthAddress(worker))
+	m.trace("WorkerConfirm", startedAt, nil, err, logging.F("worker", worker.Hex()))
+
+	return err
+}
+
+// Balance returns balances of the account.
+func (m *Client) Balance(ctx context.Context) (*sonm.BalanceReply, error) {
+	startedAt := time.Now()
+	reply, err := m.token.BalanceOf(ctx, sonm.NewEthAddress(m.account))
+	m.trace("BalanceOf", startedAt, reply, err)
+
+	return reply, err
+}
+
+// Transfer sends the amount in wei from the account to the given address.
+func (m *Client) Transfer(ctx context.Context, to common.Address, amount *big.Int) error {
+	startedAt := time.Now()
+	_, err := m.token.Transfer(ctx, &sonm.TokenTransferRequest{
+		To:     sonm.NewEthAddress(to),
+		Amount: sonm.NewBigInt(amount),
+	})
+	m.trace("Transfer", startedAt, nil, err, logging.F("to", to.Hex()), logging.F("amount", amount.String()))
+
+	return err
+}
+
+// CreateBidOrder places a bid order for the given duration, which is zero for
+// spot orders, and price in USD per second scaled by 10^18. Benchmarks are
+// the minimum resources required, like "cpu-cores".
+func (m *Client) CreateBidOrder(ctx context.Context, price *big.Int, duration time.Duration, benchmarks map[string]uint64) (*sonm.Order, error) {
+	startedAt := time.Now()
+	reply, err := m.market.CreateOrder(ctx, &sonm.BidOrder{
+		Duration: &sonm.Duration{Nanoseconds: int64(duration)},
+		Price:    &sonm.Price{PerSecond: sonm.NewBigInt(price)},
+		Identity: sonm.IdentityLevel_ANONYMOUS,
+		Resources: &sonm.BidResources{
+			Network:    &sonm.BidNetwork{},
+			Benchmarks: benchmarks,
+		},
+	})
+	m.trace("CreateOrder", startedAt, reply, err, logging.F("price", price.String()), logging.F("duration", duration))
+
+	return reply, err
+}
+
+// Orders returns active orders of the account.
+func (m *Client) Orders(ctx context.Context) ([]*sonm.Order, error) {
+	startedAt := time.Now()
+	reply, err := m.market.GetOrders(ctx, &sonm.Count{})
+	m.trace("GetOrders", startedAt, reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.GetOrders(), nil
+}
+
+// Deals returns accepted deals the account participates in either as a
+// supplier or as a consumer, along with their total count.
+func (m *Client) Deals(ctx context.Context) ([]*sonm.DWHDeal, uint64, error) {
+	startedAt := time.Now()
+	reply, err := m.dwh.GetDeals(ctx, &sonm.DealsRequest{
+		Status:    sonm.DealStatus_DEAL_ACCEPTED,
+		AnyUserID: sonm.NewEthAddress(m.account),
+		WithCount: true,
+	})
+	m.trace("GetDeals", startedAt, reply, err)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reply.GetDeals(), reply.GetCount(), nil
+}
+
+// Tasks returns statuses of tasks running within the deal by their IDs.
+// Only the consumer of the deal is allowed to list them.
+func (m *Client) Tasks(ctx context.Context, dealID *big.Int) (map[string]*sonm.TaskStatusReply, error) {
+	startedAt := time.Now()
+	reply, err := m.tasks.List(ctx, &sonm.TaskListRequest{DealID: sonm.NewBigInt(dealID)})
+	m.trace("List", startedAt, reply, err, logging.F("deal", dealID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.GetInfo(), nil
+}
+
+// trace logs the completed call at the info level, so calls show up in the
+// log console by default, while replies are only logged at the debug level.
+func (m *Client) trace(method string, startedAt time.Time, reply interface{}, err error, fields ...logging.Field) {
+	fields = append([]logging.Field{
+		logging.F("method", method),
+		logging.F("duration", time.Since(startedAt)),
+	}, fields...)
+
+	if err != nil {
+		m.log.Warn("RPC call failed", append(fields, logging.Err(err))...)
+		return
+	}
+
+	m.log.Info("RPC call", fields...)
+	if reply != nil {
+		m.log.Debug("RPC reply", logging.F("method", method), logging.F("reply", reply))
+	}
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorClass groups gRPC status codes by what the caller can do about them.
+type ErrorClass int
+
+const (
+	ClassOK ErrorClass = iota
+	// ClassUnknown covers errors that are not gRPC statuses or can't be
+	// classified otherwise.
+	ClassUnknown
+	// ClassUnavailable means that the node can't be reached or didn't respond
+	// in time, so the request can be retried later.
+	ClassUnavailable
+	// ClassUnauthorized means that the account is not allowed to perform the
+	// request.
+	ClassUnauthorized
+	// ClassNotFound means that the requested entity does not exist.
+	ClassNotFound
+	// ClassRejected means that the request is invalid or conflicts with the
+	// current state and won't succeed when retried as is.
+	ClassRejected
+	// ClassInternal means that the node failed to handle the request.
+	ClassInternal
+)
+
+// Classify maps the given error into its class.
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ClassOK
+	}
+
+	switch err {
+	case context.DeadlineExceeded, context.Canceled:
+		return ClassUnavailable
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return ClassUnknown
+	}
+
+	switch st.Code() {
+	case codes.OK:
+		return ClassOK
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.ResourceExhausted:
+		return ClassUnavailable
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ClassUnauthorized
+	case codes.NotFound:
+		return ClassNotFound
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.AlreadyExists, codes.OutOfRange, codes.Aborted:
+		return ClassRejected
+	case codes.Internal, codes.Unimplemented, codes.DataLoss:
+		return ClassInternal
+	default:
+		return ClassUnknown
+	}
+}
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/signer"
+	"github.com/sonm-io/core/insonmnia/auth"
+	"github.com/sonm-io/core/util"
+	"github.com/sonm-io/core/util/xgrpc"
+	"google.golang.org/grpc"
+)
+
+const (
+	DefaultDialTimeout = 3 * time.Second
+)
+
+// Dial establishes a gRPC connection to the node at the given address,
+// authenticating on behalf of the signer account.
+//
+// The node authenticates clients by TLS certificates signed with the account
+// key. Signers holding the key in memory rely on the core library rotating
+// certificates, others are asked to sign each certificate.
+func Dial(ctx context.Context, addr string, s signer.Signer) (*grpc.ClientConn, error) {
+	var TLSConfig *tls.Config
+	if holder, ok := s.(signer.KeyHolder); ok {
+		privateKey := holder.PrivateKey()
+		if privateKey == nil {
+			return nil, errors.New("signer is closed")
+		}
+
+		_, config, err := util.NewHitlessCertRotator(ctx, privateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		TLSConfig = config
+	} else {
+		TLSConfig = newCertificateSigner(s).TLSConfig()
+	}
+
+	credentials := auth.NewWalletAuthenticator(util.NewTLS(TLSConfig), s.Account())
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultDialTimeout)
+	defer cancel()
+
+	return xgrpc.NewClient(ctx, addr, credentials, grpc.WithBlock())
+}
@@ -0,0 +1,206 @@
+package session
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/signer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LockReason describes why the session was locked.
+type LockReason string
+
+const (
+	LockManual LockReason = "manual"
+	LockIdle   LockReason = "idle"
+)
+
+// Locked is emitted by Session.OnLocked.
+type Locked struct {
+	Account common.Address
+	Reason  LockReason
+}
+
+// Unlocker decrypts the key of the given account from the keystore.
+type Unlocker func(keystorePath string, account common.Address, password string) (*ecdsa.PrivateKey, error)
+
+// Session holds the signer of the current account.
+//
+// The session is locked either manually or after a period without user
+// activity, in which case the signer is closed, zeroing the key it holds, and
+// OnLocked is emitted. The
+// account and its keystore are remembered, so the session can be unlocked
+// again by asking for the password only.
+type Session struct {
+	mu           sync.Mutex
+	account      common.Address
+	keystorePath string
+	signer       signer.Signer
+	timeout      time.Duration
+	timer        *time.Timer
+	lastActivity time.Time
+	unlocker     Unlocker
+
+	// OnLocked is emitted with Locked each time the session is locked.
+	OnLocked *mp.Signal
+}
+
+// NewSession constructs a locked session. Zero timeout disables locking on
+// idle.
+func NewSession(timeout time.Duration, unlocker Unlocker, router *mp.Router) *Session {
+	return &Session{
+		timeout:  timeout,
+		unlocker: unlocker,
+		OnLocked: router.NewSignal(),
+	}
+}
+
+// Unlock starts the session with the given signer, which from now on is
+// owned by the session and is closed when it is locked.
+//
+// The keystore path is empty for signers not backed by a keystore.
+func (m *Session) Unlock(account common.Address, keystorePath string, s signer.Signer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.signer != nil && m.signer != s {
+		m.signer.Close()
+	}
+
+	m.account = account
+	m.keystorePath = keystorePath
+	m.signer = s
+	m.lastActivity = time.Now()
+
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	if m.timeout > 0 {
+		m.timer = time.AfterFunc(m.timeout, m.onTimer)
+	}
+}
+
+// Lock closes the signer and emits OnLocked. Does nothing if the session is
+// already locked.
+func (m *Session) Lock(reason LockReason) {
+	m.mu.Lock()
+	if m.signer == nil {
+		m.mu.Unlock()
+		return
+	}
+
+	m.close()
+	account := m.account
+	m.mu.Unlock()
+
+	m.OnLocked.Emit(&Locked{Account: account, Reason: reason})
+}
+
+// Close closes the signer without notifying anyone, which is meant to be done
+// on exit.
+func (m *Session) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.close()
+}
+
+func (m *Session) close() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+
+	if m.signer != nil {
+		m.signer.Close()
+		m.signer = nil
+	}
+}
+
+// Touch resets the idle timeout.
+func (m *Session) Touch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastActivity = time.Now()
+}
+
+func (m *Session) IsLocked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.signer == nil
+}
+
+// Account returns the address of the last unlocked account.
+func (m *Session) Account() common.Address {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.account
+}
+
+// KeystorePath returns the keystore of the last unlocked account.
+func (m *Session) KeystorePath() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.keystorePath
+}
+
+// Verify checks that the given password unlocks the session account, which
+// is used to re-authenticate the user before sensitive actions.
+//
+// Sessions not backed by a keystore have no password to check, which is
+// fine, because external signers confirm operations on their own, while
+// keys from the environment are meant for automation.
+//
+// Decrypting the keystore is deliberately slow, so this should not be called
+// from the UI thread.
+func (m *Session) Verify(password string) error {
+	m.mu.Lock()
+	account, keystorePath, locked := m.account, m.keystorePath, m.signer == nil
+	m.mu.Unlock()
+
+	if locked {
+		return fmt.Errorf("session is locked")
+	}
+
+	if keystorePath == "" {
+		m.Touch()
+		return nil
+	}
+
+	key, err := m.unlocker(keystorePath, account, password)
+	if err != nil {
+		return err
+	}
+	signer.Zero(key)
+
+	m.Touch()
+
+	return nil
+}
+
+func (m *Session) onTimer() {
+	m.mu.Lock()
+	if m.signer == nil {
+		m.mu.Unlock()
+		return
+	}
+
+	idle := time.Since(m.lastActivity)
+	if idle < m.timeout {
+		m.timer = time.AfterFunc(m.timeout-idle, m.onTimer)
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	m.Lock(LockIdle)
+}
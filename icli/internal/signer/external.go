@@ -0,0 +1,183 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// DefaultExternalTimeout limits a single request to the external signer,
+	// which may wait for the user to approve it.
+	DefaultExternalTimeout = 2 * time.Minute
+)
+
+type jsonRequest struct {
+	Version string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonError      `json:"error"`
+}
+
+// ExternalSigner asks a separate process listening on a Unix socket to sign
+// hashes, so the key is never decrypted into icli memory.
+//
+// Similar to Clef, the process speaks JSON-RPC 2.0 and must support the
+// following methods:
+//   - "account_list" returning the list of hex-encoded addresses;
+//   - "account_signHash" accepting the address and the hex-encoded hash and
+//     returning the hex-encoded 65-byte signature.
+//
+// Each request is sent over a new connection. Signatures are verified to be
+// made by the expected account.
+type ExternalSigner struct {
+	path    string
+	account common.Address
+	timeout time.Duration
+}
+
+// NewExternalSigner connects to the signer listening on the socket at the
+// given path. If the account is zero, the signer must manage exactly one
+// account, which is used then.
+func NewExternalSigner(path string, account common.Address) (*ExternalSigner, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ExternalSigner{
+		path:    path,
+		timeout: DefaultExternalTimeout,
+	}
+
+	accounts, err := m.Accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case account == (common.Address{}) && len(accounts) == 1:
+		m.account = accounts[0]
+	case account == (common.Address{}):
+		return nil, fmt.Errorf("external signer manages %d accounts, specify one explicitly", len(accounts))
+	default:
+		for _, candidate := range accounts {
+			if candidate == account {
+				m.account = account
+			}
+		}
+
+		if m.account != account {
+			return nil, fmt.Errorf("account %s is not managed by the external signer", account.Hex())
+		}
+	}
+
+	return m, nil
+}
+
+// Accounts returns addresses of all accounts managed by the signer.
+func (m *ExternalSigner) Accounts() ([]common.Address, error) {
+	var addrs []string
+	if err := m.call("account_list", &addrs); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]common.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("external signer returned invalid address %q", addr)
+		}
+
+		accounts = append(accounts, common.HexToAddress(addr))
+	}
+
+	return accounts, nil
+}
+
+func (m *ExternalSigner) Account() common.Address {
+	return m.account
+}
+
+func (m *ExternalSigner) SignHash(hash []byte) ([]byte, error) {
+	var result string
+	if err := m.call("account_signHash", &result, m.account.Hex(), hexutil.Encode(hash)); err != nil {
+		return nil, err
+	}
+
+	signature, err := hexutil.Decode(result)
+	if err != nil {
+		return nil, fmt.Errorf("external signer returned invalid signature: %v", err)
+	}
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("external signer returned signature of %d bytes, expected 65", len(signature))
+	}
+
+	// Signers following Ethereum conventions return V as 27 or 28.
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return nil, fmt.Errorf("external signer returned invalid signature: %v", err)
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != m.account {
+		return nil, fmt.Errorf("external signer signed with %s instead of %s", signer.Hex(), m.account.Hex())
+	}
+
+	return signature, nil
+}
+
+// Close does nothing, because the key is held by the external signer.
+func (m *ExternalSigner) Close() error {
+	return nil
+}
+
+func (m *ExternalSigner) call(method string, result interface{}, params ...interface{}) error {
+	conn, err := net.DialTimeout("unix", m.path, m.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to external signer: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(m.timeout)); err != nil {
+		return err
+	}
+
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	request := &jsonRequest{Version: "2.0", ID: 1, Method: method, Params: params}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return fmt.Errorf("failed to send request to external signer: %v", err)
+	}
+
+	response := &jsonResponse{}
+	if err := json.NewDecoder(conn).Decode(response); err != nil {
+		return fmt.Errorf("failed to read response of external signer: %v", err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("external signer: %s", response.Error.Message)
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sonm-io/core/accounts"
+)
+
+var errClosed = errors.New("signer is closed")
+
+// KeySigner signs using the private key held in memory.
+type KeySigner struct {
+	mu      sync.Mutex
+	account common.Address
+	key     *ecdsa.PrivateKey
+}
+
+// NewKeySigner constructs a signer owning the given key, which is zeroed on
+// Close.
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		account: crypto.PubkeyToAddress(key.PublicKey),
+		key:     key,
+	}
+}
+
+// NewKeystoreSigner decrypts the key of the given account from the keystore.
+//
+// Decrypting the keystore is deliberately slow, so this should not be called
+// from the UI thread.
+func NewKeystoreSigner(keystorePath string, account common.Address, password string) (*KeySigner, error) {
+	key, err := LoadKey(keystorePath, account, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySigner(key), nil
+}
+
+// NewEnvSigner reads the hex-encoded private key from the environment
+// variable with the given name, which is meant for automation.
+//
+// The variable is removed from the environment, so it is not inherited by
+// child processes, like desktop notification commands.
+func NewEnvSigner(name string) (*KeySigner, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	os.Unsetenv(name)
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(value), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in %s: %v", name, err)
+	}
+
+	return NewKeySigner(key), nil
+}
+
+func (m *KeySigner) Account() common.Address {
+	return m.account
+}
+
+func (m *KeySigner) PrivateKey() *ecdsa.PrivateKey {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.key
+}
+
+func (m *KeySigner) SignHash(hash []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.key == nil {
+		return nil, errClosed
+	}
+
+	return crypto.Sign(hash, m.key)
+}
+
+func (m *KeySigner) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	Zero(m.key)
+	m.key = nil
+
+	return nil
+}
+
+// LoadKey decrypts the key of the given account from the keystore.
+func LoadKey(keystorePath string, account common.Address, password string) (*ecdsa.PrivateKey, error) {
+	keystore, err := accounts.NewMultiKeystore(accounts.NewKeystoreConfig(keystorePath), accounts.NewStaticPassPhraser(""))
+	if err != nil {
+		return nil, err
+	}
+
+	return keystore.GetKeyWithPass(account, password)
+}
+
+// Zero overwrites the private scalar of the given key.
+//
+// Note that this is the best effort only, because copies made while the key
+// was decrypted or used are out of reach.
+func Zero(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+
+	words := key.D.Bits()
+	for id := range words {
+		words[id] = 0
+	}
+	key.D.SetInt64(0)
+}
@@ -0,0 +1,41 @@
+// Package signer abstracts the way signatures of an account are produced, so
+// that the private key does not necessarily have to be decrypted into icli
+// memory.
+package signer
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	KindKeystore = "keystore"
+	KindEnv      = "env"
+	KindExternal = "external"
+
+	DefaultKeyEnv     = "ICLI_PRIVATE_KEY"
+	DefaultSocketPath = "~/.sonm/signer.ipc"
+)
+
+// Kinds lists names of supported signers.
+var Kinds = []string{KindKeystore, KindEnv, KindExternal}
+
+// Signer signs data on behalf of a single account.
+type Signer interface {
+	// Account returns the address of the account.
+	Account() common.Address
+	// SignHash signs the given 32-byte hash, returning the signature in the
+	// [R || S || V] format, where V is 0 or 1.
+	SignHash(hash []byte) ([]byte, error)
+	// Close releases the signer, zeroing the key if it is held in memory.
+	// The signer must not be used afterwards.
+	Close() error
+}
+
+// KeyHolder is implemented by signers holding the private key in memory.
+type KeyHolder interface {
+	Signer
+	// PrivateKey returns the key or nil if the signer is closed.
+	PrivateKey() *ecdsa.PrivateKey
+}
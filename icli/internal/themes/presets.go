@@ -0,0 +1,129 @@
+package themes
+
+var presets = map[string]string{
+	"dark":          darkTheme,
+	"light":         lightTheme,
+	"high-contrast": highContrastTheme,
+	"monochrome":    monochromeTheme,
+}
+
+const (
+	darkTheme = `
+styles:
+  list.item.match:     {fg: yellow, bold: true}
+  list.item.selected:  {reverse: true}
+  table.cell.selected: {reverse: true}
+  button.focused:      {reverse: true}
+  yellow:              {fg: yellow}
+  label.logo:          {fg: blue}
+  label.title:         {fg: blue, bold: true}
+  label.bold:          {bold: true}
+  label.highlight:     {bold: true, underline: true}
+  label.normal:        {bold: true, underline: false}
+  label.ok:            {}
+  label.succ:          {fg: green}
+  label.success:       {fg: green}
+  label.warn:          {fg: yellow}
+  label.error:         {fg: red}
+  toast.info:          {fg: black, bg: white}
+  toast.success:       {fg: black, bg: green}
+  toast.warn:          {fg: black, bg: yellow}
+  toast.error:         {fg: white, bg: red, bold: true}
+  tab:                 {}
+  tab.selected:        {reverse: true, bold: true}
+  scrollbar:           {fg: "240"}
+  scrollbar.thumb:     {fg: white}
+  chart:               {fg: green}
+  chart.axis:          {fg: "244"}
+`
+
+	lightTheme = `
+styles:
+  list.item.match:     {fg: "136", bold: true}
+  list.item.selected:  {reverse: true}
+  table.cell.selected: {reverse: true}
+  button.focused:      {reverse: true}
+  yellow:              {fg: "136"}
+  label.logo:          {fg: "25"}
+  label.title:         {fg: "25", bold: true}
+  label.bold:          {bold: true}
+  label.highlight:     {bold: true, underline: true}
+  label.normal:        {bold: true, underline: false}
+  label.ok:            {}
+  label.succ:          {fg: "28"}
+  label.success:       {fg: "28"}
+  label.warn:          {fg: "136"}
+  label.error:         {fg: "124"}
+  toast.info:          {fg: white, bg: "25"}
+  toast.success:       {fg: white, bg: "28"}
+  toast.warn:          {fg: black, bg: "214"}
+  toast.error:         {fg: white, bg: "124", bold: true}
+  tab:                 {}
+  tab.selected:        {fg: white, bg: "25", bold: true}
+  scrollbar:           {fg: "250"}
+  scrollbar.thumb:     {fg: "25"}
+  chart:               {fg: "28"}
+  chart.axis:          {fg: "244"}
+`
+
+	highContrastTheme = `
+styles:
+  list.item.match:     {fg: "#ffff00", bold: true, underline: true}
+  list.item.selected:  {fg: black, bg: yellow, bold: true}
+  table.cell.selected: {fg: black, bg: yellow, bold: true}
+  button.focused:      {fg: black, bg: yellow, bold: true}
+  entry.focused:       {underline: true}
+  dialog:              {fg: white, bg: black}
+  yellow:              {fg: "#ffff00", bold: true}
+  label.logo:          {fg: "#ffffff", bold: true}
+  label.title:         {fg: "#ffffff", bold: true, underline: true}
+  label.bold:          {bold: true}
+  label.highlight:     {fg: "#ffff00", bold: true, underline: true}
+  label.normal:        {bold: true, underline: false}
+  label.ok:            {fg: "#ffffff"}
+  label.succ:          {fg: "#00ff00", bold: true}
+  label.success:       {fg: "#00ff00", bold: true}
+  label.warn:          {fg: "#ffff00", bold: true}
+  label.error:         {fg: "#ff0000", bold: true}
+  toast.info:          {fg: black, bg: "#ffffff", bold: true}
+  toast.success:       {fg: black, bg: "#00ff00", bold: true}
+  toast.warn:          {fg: black, bg: "#ffff00", bold: true}
+  toast.error:         {fg: "#ffffff", bg: "#ff0000", bold: true}
+  tab:                 {fg: "#ffffff"}
+  tab.selected:        {fg: black, bg: yellow, bold: true}
+  scrollbar:           {fg: "#ffffff"}
+  scrollbar.thumb:     {fg: yellow}
+  chart:               {fg: cyan}
+  chart.axis:          {fg: "#ffffff"}
+`
+
+	monochromeTheme = `
+styles:
+  list.item.match:     {underline: true}
+  list.item.selected:  {reverse: true}
+  table.cell.selected: {reverse: true}
+  button.focused:      {reverse: true}
+  entry.focused:       {underline: true}
+  yellow:              {bold: true}
+  label.logo:          {bold: true}
+  label.title:         {bold: true, underline: true}
+  label.bold:          {bold: true}
+  label.highlight:     {bold: true, underline: true}
+  label.normal:        {bold: true, underline: false}
+  label.ok:            {}
+  label.succ:          {bold: true}
+  label.success:       {bold: true}
+  label.warn:          {underline: true}
+  label.error:         {reverse: true}
+  toast.info:          {reverse: true}
+  toast.success:       {reverse: true}
+  toast.warn:          {reverse: true, underline: true}
+  toast.error:         {reverse: true, bold: true}
+  tab:                 {}
+  tab.selected:        {reverse: true, bold: true}
+  scrollbar:           {}
+  scrollbar.thumb:     {bold: true}
+  chart:               {}
+  chart.axis:          {}
+`
+)
@@ -0,0 +1,251 @@
+package themes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefaultTheme = "dark"
+)
+
+const (
+	// tui-go passes colors it doesn't know as is to tcell, which treats
+	// values with this bit set as 24-bit RGB colors and the rest as indices
+	// in the 256-color palette.
+	colorIsRGB = 1 << 24
+	// Number of named colors known by tui-go, i.e. values up to this one are
+	// not passed to tcell verbatim.
+	namedColorCount = 9
+)
+
+var namedColors = map[string]tui.Color{
+	"default": tui.ColorDefault,
+	"black":   tui.ColorBlack,
+	"white":   tui.ColorWhite,
+	"red":     tui.ColorRed,
+	"green":   tui.ColorGreen,
+	"blue":    tui.ColorBlue,
+	"cyan":    tui.ColorCyan,
+	"magenta": tui.ColorMagenta,
+	"yellow":  tui.ColorYellow,
+}
+
+// Standard xterm RGB values of the first palette colors, which collide with
+// tui-go named colors and therefore must be passed as RGB.
+var lowPaletteColors = [namedColorCount]int32{
+	0x000000, 0x800000, 0x008000, 0x808000, 0x000080, 0x800080, 0x008080, 0xc0c0c0, 0x808080,
+}
+
+// knownStyles contains all style names that are used either by tui-go itself
+// or by icli widgets.
+var knownStyles = map[string]struct{}{
+	"normal":              {},
+	"box":                 {},
+	"box.border":          {},
+	"box.focused":         {},
+	"box.focused.border":  {},
+	"button":              {},
+	"button.focused":      {},
+	"entry":               {},
+	"entry.focused":       {},
+	"list.item":           {},
+	"list.item.selected":  {},
+	"list.item.match":     {},
+	"table.cell":          {},
+	"table.cell.selected": {},
+	"statusbar":           {},
+	"dialog":              {},
+	"yellow":              {},
+	"label":               {},
+	"label.logo":          {},
+	"label.title":         {},
+	"label.bold":          {},
+	"label.highlight":     {},
+	"label.normal":        {},
+	"label.ok":            {},
+	"label.succ":          {},
+	"label.success":       {},
+	"label.warn":          {},
+	"label.error":         {},
+	"toast.info":          {},
+	"toast.success":       {},
+	"toast.warn":          {},
+	"toast.error":         {},
+	"tab":                 {},
+	"tab.selected":        {},
+	"scrollbar":           {},
+	"scrollbar.thumb":     {},
+	"chart":               {},
+	"chart.axis":          {},
+}
+
+// StyleConfig is a YAML representation of a tui.Style.
+//
+// Colors are specified either by name ("red"), by 256-color palette index
+// ("208") or as 24-bit RGB value ("#ff8700"). Decorations that are omitted
+// are inherited from the parent widget.
+type StyleConfig struct {
+	Fg        string `yaml:"fg"`
+	Bg        string `yaml:"bg"`
+	Bold      *bool  `yaml:"bold"`
+	Underline *bool  `yaml:"underline"`
+	Reverse   *bool  `yaml:"reverse"`
+}
+
+// Config describes a theme file.
+type Config struct {
+	Styles map[string]StyleConfig `yaml:"styles"`
+}
+
+// Presets returns names of all shipped themes.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load constructs a theme either from the shipped preset with the given name
+// or from the YAML file at the given path.
+func Load(nameOrPath string) (*tui.Theme, error) {
+	if nameOrPath == "" {
+		nameOrPath = DefaultTheme
+	}
+
+	content, ok := presets[nameOrPath]
+	if !ok {
+		path, err := homedir.Expand(nameOrPath)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load theme %q, which is neither a file nor one of presets (%s): %v", nameOrPath, strings.Join(Presets(), ", "), err)
+		}
+
+		content = string(data)
+	}
+
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict([]byte(content), cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse theme %q: %v", nameOrPath, err)
+	}
+
+	return NewTheme(cfg)
+}
+
+// NewTheme validates the given theme config and converts it into a tui.Theme.
+func NewTheme(cfg *Config) (*tui.Theme, error) {
+	theme := tui.NewTheme()
+
+	for name, styleCfg := range cfg.Styles {
+		if _, ok := knownStyles[name]; !ok {
+			return nil, fmt.Errorf("unknown style name %q", name)
+		}
+
+		style, err := newStyle(styleCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid style %q: %v", name, err)
+		}
+
+		theme.SetStyle(name, style)
+	}
+
+	return theme, nil
+}
+
+func newStyle(cfg StyleConfig) (tui.Style, error) {
+	fg, err := ParseColor(cfg.Fg)
+	if err != nil {
+		return tui.Style{}, err
+	}
+
+	bg, err := ParseColor(cfg.Bg)
+	if err != nil {
+		return tui.Style{}, err
+	}
+
+	return tui.Style{
+		Fg:        fg,
+		Bg:        bg,
+		Bold:      decoration(cfg.Bold),
+		Underline: decoration(cfg.Underline),
+		Reverse:   decoration(cfg.Reverse),
+	}, nil
+}
+
+// ParseColor parses a color name, 256-color palette index or "#rrggbb" RGB
+// value. An empty string means the default color.
+func ParseColor(value string) (tui.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if value == "" {
+		return tui.ColorDefault, nil
+	}
+
+	if color, ok := namedColors[value]; ok {
+		return color, nil
+	}
+
+	if strings.HasPrefix(value, "#") {
+		if len(value) != 7 {
+			return 0, fmt.Errorf("invalid RGB color %q, expected #rrggbb", value)
+		}
+
+		rgb, err := strconv.ParseUint(value[1:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid RGB color %q: %v", value, err)
+		}
+
+		return tui.Color(colorIsRGB | int32(rgb)), nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown color %q, expected name, palette index (0-255) or #rrggbb", value)
+	}
+
+	if id < namedColorCount {
+		return tui.Color(colorIsRGB | lowPaletteColors[id]), nil
+	}
+
+	return tui.Color(id), nil
+}
+
+// ColorStyle registers the style with the given foreground color in the
+// theme and returns its name, so colors specified outside of the theme, like
+// account colors, can be used where style names are expected.
+func ColorStyle(theme *tui.Theme, color string) (string, error) {
+	fg, err := ParseColor(color)
+	if err != nil {
+		return "", err
+	}
+
+	name := "color." + strings.ToLower(strings.TrimSpace(color))
+	theme.SetStyle(name, tui.Style{Fg: fg})
+
+	return name, nil
+}
+
+func decoration(v *bool) tui.Decoration {
+	switch {
+	case v == nil:
+		return tui.DecorationInherit
+	case *v:
+		return tui.DecorationOn
+	default:
+		return tui.DecorationOff
+	}
+}
@@ -0,0 +1,239 @@
+// Package units parses and formats values SONM deals with: token amounts,
+// prices, durations and sizes of resources.
+package units
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Decimals is the number of decimal places of the SNM token and of
+	// USD prices.
+	Decimals = 18
+	// priceDecimals is the number of decimal places prices are formatted
+	// with.
+	priceDecimals = 12
+	// maxDays is the number of days still fitting into time.Duration.
+	maxDays = int64(1<<63-1) / int64(24*time.Hour)
+)
+
+var hour = big.NewInt(int64(time.Hour / time.Second))
+
+// ParseAmount parses a token amount, like "1.5", "1.5 SNM" or "100 wei",
+// into wei. Numbers without unit are SNM.
+func ParseAmount(text string) (*big.Int, error) {
+	number, unit := split(text)
+
+	switch strings.ToLower(unit) {
+	case "", "snm":
+		amount, err := parseDecimal(number, Decimals)
+		if err != nil {
+			return nil, fmt.Errorf("malformed amount %q: %v", text, err)
+		}
+		return amount, nil
+	case "wei":
+		amount, err := parseDecimal(number, 0)
+		if err != nil {
+			return nil, fmt.Errorf("malformed amount %q: %v", text, err)
+		}
+		return amount, nil
+	default:
+		return nil, fmt.Errorf("unknown unit %q of amount %q, expected SNM or wei", unit, text)
+	}
+}
+
+// FormatAmount formats an amount in wei as SNM, like "1.5 SNM".
+func FormatAmount(amount *big.Int) string {
+	return formatDecimal(amount, Decimals) + " SNM"
+}
+
+// AmountFloat converts an amount in wei to an approximate number of SNM,
+// which is precise enough for charts.
+func AmountFloat(amount *big.Int) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(pow10(Decimals))).Float64()
+	return value
+}
+
+// ParsePrice parses a price, like "0.5 USD/h" or "0.0001 USD/s", into USD
+// per second scaled by 10^18, which is how orders and deals keep prices.
+// Numbers without unit are USD/h.
+func ParsePrice(text string) (*big.Int, error) {
+	number, unit := split(text)
+
+	price, err := parseDecimal(number, Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("malformed price %q: %v", text, err)
+	}
+
+	switch strings.ToLower(unit) {
+	case "", "usd/h":
+		return price.Quo(price, hour), nil
+	case "usd/s":
+		return price, nil
+	default:
+		return nil, fmt.Errorf("unknown unit %q of price %q, expected USD/h or USD/s", unit, text)
+	}
+}
+
+// FormatPrice formats a price per second scaled by 10^18 as USD per hour,
+// like "0.5 USD/h".
+//
+// Prices per hour lose precision when converted to prices per second, so
+// the result is rounded to priceDecimals places to get "0.5" back instead of
+// "0.4999999999999984".
+func FormatPrice(price *big.Int) string {
+	unit := pow10(Decimals - priceDecimals)
+
+	perHour := new(big.Int).Mul(price, hour)
+	perHour.Add(perHour, new(big.Int).Rsh(unit, 1))
+	perHour.Sub(perHour, new(big.Int).Mod(perHour, unit))
+
+	return formatDecimal(perHour, Decimals) + " USD/h"
+}
+
+// ParseDuration parses a duration, like "1h30m" or "2d12h". In addition to
+// units of time.ParseDuration it accepts days, which are 24 hours.
+func ParseDuration(text string) (time.Duration, error) {
+	malformed := fmt.Errorf("malformed duration %q, expected a value like 1h30m or 2d", text)
+
+	days := time.Duration(0)
+	rest := strings.TrimSpace(text)
+	if id := strings.IndexByte(rest, 'd'); id >= 0 {
+		n, ok := new(big.Int).SetString(rest[:id], 10)
+		if !ok || n.Sign() < 0 || !n.IsInt64() || n.Int64() > maxDays {
+			return 0, malformed
+		}
+
+		days = time.Duration(n.Int64()) * 24 * time.Hour
+		rest = rest[id+1:]
+		if rest == "" {
+			return days, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(rest)
+	if err != nil || duration < 0 || duration > math.MaxInt64-days {
+		return 0, malformed
+	}
+
+	return days + duration, nil
+}
+
+// FormatDuration formats a duration omitting zero units, like "1h30m" or
+// "2d12h".
+func FormatDuration(duration time.Duration) string {
+	if duration < time.Second {
+		return duration.String()
+	}
+
+	text := ""
+	if days := duration / (24 * time.Hour); days > 0 {
+		text = fmt.Sprintf("%dd", days)
+		duration -= days * 24 * time.Hour
+	}
+
+	if duration == 0 {
+		return text
+	}
+
+	rest := duration.String()
+	if strings.HasSuffix(rest, "m0s") {
+		rest = rest[:len(rest)-2]
+	}
+	if strings.HasSuffix(rest, "h0m") {
+		rest = rest[:len(rest)-2]
+	}
+
+	return text + rest
+}
+
+// sizeUnits are binary units of sizes, each 1024 times larger than the
+// previous one.
+var sizeUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// FormatSize formats a size in bytes using the largest binary unit keeping
+// the value at least 1, rounded to one decimal place, like "512 MiB" or
+// "1.5 GiB".
+func FormatSize(size uint64) string {
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(sizeUnits)-1 {
+		value /= 1024
+		unit++
+	}
+
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64) + " " + sizeUnits[unit]
+}
+
+// pow10 returns 10^decimals.
+func pow10(decimals int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
+
+// split splits the text into a number and a unit following it, which may be
+// separated by spaces.
+func split(text string) (string, string) {
+	text = strings.TrimSpace(text)
+
+	id := strings.IndexFunc(text, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if id < 0 {
+		return text, ""
+	}
+
+	return text[:id], strings.TrimSpace(text[id:])
+}
+
+// parseDecimal parses a non-negative decimal number into an integer scaled
+// by 10^decimals.
+func parseDecimal(text string, decimals int) (*big.Int, error) {
+	whole, frac := text, ""
+	if id := strings.IndexByte(text, '.'); id >= 0 {
+		whole, frac = text[:id], text[id+1:]
+	}
+	switch {
+	case decimals == 0 && frac != "":
+		return nil, fmt.Errorf("expected a whole number")
+	case len(frac) > decimals:
+		return nil, fmt.Errorf("more than %d decimal places", decimals)
+	}
+
+	if whole+frac == "" || strings.ContainsAny(whole+frac, "+-") {
+		return nil, fmt.Errorf("expected a number like 1.5")
+	}
+
+	value, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("expected a number like 1.5")
+	}
+
+	return value, nil
+}
+
+// formatDecimal formats an integer scaled by 10^decimals as a decimal number
+// without trailing zeros.
+func formatDecimal(value *big.Int, decimals int) string {
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+		value = new(big.Int).Neg(value)
+	}
+
+	digits := value.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + frac
+}
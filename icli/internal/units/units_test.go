@@ -0,0 +1,210 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{"1.5", "1500000000000000000"},
+		{"1.5 SNM", "1500000000000000000"},
+		{"1.5snm", "1500000000000000000"},
+		{" 2 SNM ", "2000000000000000000"},
+		{".5", "500000000000000000"},
+		{"0.000000000000000001", "1"},
+		{"100 wei", "100"},
+		{"100WEI", "100"},
+	}
+
+	for _, c := range cases {
+		amount, err := ParseAmount(c.text)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) failed: %v", c.text, err)
+			continue
+		}
+
+		if amount.String() != c.expected {
+			t.Errorf("ParseAmount(%q) = %s, expected %s", c.text, amount, c.expected)
+		}
+	}
+
+	for _, text := range []string{"", "SNM", "1 ETH", "-1", "1.5 wei", "0.0000000000000000001", "1.2.3", "1e18"} {
+		if amount, err := ParseAmount(text); err == nil {
+			t.Errorf("ParseAmount(%q) = %s, expected an error", text, amount)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		amount   string
+		expected string
+	}{
+		{"0", "0 SNM"},
+		{"1", "0.000000000000000001 SNM"},
+		{"1500000000000000000", "1.5 SNM"},
+		{"12000000000000000000", "12 SNM"},
+		{"-1500000000000000000", "-1.5 SNM"},
+	}
+
+	for _, c := range cases {
+		if actual := FormatAmount(bigInt(c.amount)); actual != c.expected {
+			t.Errorf("FormatAmount(%s) = %q, expected %q", c.amount, actual, c.expected)
+		}
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{"1", "277777777777777"},
+		{"0.5 USD/h", "138888888888888"},
+		{"0.5usd/h", "138888888888888"},
+		{"0.0001 USD/s", "100000000000000"},
+		{"0", "0"},
+	}
+
+	for _, c := range cases {
+		price, err := ParsePrice(c.text)
+		if err != nil {
+			t.Errorf("ParsePrice(%q) failed: %v", c.text, err)
+			continue
+		}
+
+		if price.String() != c.expected {
+			t.Errorf("ParsePrice(%q) = %s, expected %s", c.text, price, c.expected)
+		}
+	}
+
+	for _, text := range []string{"", "USD/h", "1 USD", "1 SNM/h", "-1 USD/h", "0.0000000000000000001"} {
+		if price, err := ParsePrice(text); err == nil {
+			t.Errorf("ParsePrice(%q) = %s, expected an error", text, price)
+		}
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	cases := []struct {
+		price    string
+		expected string
+	}{
+		{"0", "0 USD/h"},
+		{"277777777777777", "1 USD/h"},
+		{"138888888888888", "0.5 USD/h"},
+		{"100000000000000", "0.36 USD/h"},
+		{"1", "0 USD/h"},
+		{"278", "0.000000000001 USD/h"},
+	}
+
+	for _, c := range cases {
+		if actual := FormatPrice(bigInt(c.price)); actual != c.expected {
+			t.Errorf("FormatPrice(%s) = %q, expected %q", c.price, actual, c.expected)
+		}
+	}
+
+	// Prices per hour survive the round trip through prices per second.
+	for _, text := range []string{"0.5 USD/h", "1 USD/h", "0.01 USD/h", "123.456 USD/h"} {
+		price, err := ParsePrice(text)
+		if err != nil {
+			t.Errorf("ParsePrice(%q) failed: %v", text, err)
+			continue
+		}
+
+		if actual := FormatPrice(price); actual != text {
+			t.Errorf("FormatPrice(ParsePrice(%q)) = %q", text, actual)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{" 45s ", 45 * time.Second},
+		{"2d", 48 * time.Hour},
+		{"2d12h", 60 * time.Hour},
+		{"1d30m", 24*time.Hour + 30*time.Minute},
+		{"0d", 0},
+		{"106751d", 106751 * 24 * time.Hour},
+	}
+
+	for _, c := range cases {
+		duration, err := ParseDuration(c.text)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %v", c.text, err)
+			continue
+		}
+
+		if duration != c.expected {
+			t.Errorf("ParseDuration(%q) = %v, expected %v", c.text, duration, c.expected)
+		}
+	}
+
+	for _, text := range []string{"", "d", "1x", "-1h", "-1d", "1.5d", "1d-1h", "106752d", "106751d23h59m", "2562048h"} {
+		if duration, err := ParseDuration(text); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, expected an error", text, duration)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{500 * time.Millisecond, "500ms"},
+		{time.Minute, "1m"},
+		{2 * time.Hour, "2h"},
+		{90 * time.Minute, "1h30m"},
+		{time.Hour + 30*time.Second, "1h0m30s"},
+		{48 * time.Hour, "2d"},
+		{60 * time.Hour, "2d12h"},
+		{48*time.Hour + 30*time.Second, "2d30s"},
+	}
+
+	for _, c := range cases {
+		if actual := FormatDuration(c.duration); actual != c.expected {
+			t.Errorf("FormatDuration(%v) = %q, expected %q", c.duration, actual, c.expected)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	cases := []struct {
+		size     uint64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1 KiB"},
+		{512 << 20, "512 MiB"},
+		{3 << 29, "1.5 GiB"},
+		{1<<30 + 1<<20, "1 GiB"},
+		{1<<64 - 1, "16 EiB"},
+	}
+
+	for _, c := range cases {
+		if actual := FormatSize(c.size); actual != c.expected {
+			t.Errorf("FormatSize(%d) = %q, expected %q", c.size, actual, c.expected)
+		}
+	}
+}
+
+func bigInt(text string) *big.Int {
+	value, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		panic("malformed integer " + text)
+	}
+
+	return value
+}
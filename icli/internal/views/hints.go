@@ -0,0 +1,172 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/3Hren/sonmui/icli/internal/addressbook"
+	"github.com/3Hren/sonmui/icli/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Hint is a single completion suggestion.
+type Hint struct {
+	// Text replaces the entry text when the hint is chosen.
+	Text string
+	// Description is shown next to the text and takes part in filtering.
+	Description string
+	// Value is an optional payload passed to the OnHintActivated callback,
+	// for example the account a keystore hint points to.
+	Value interface{}
+}
+
+func (m Hint) label() string {
+	if m.Description == "" {
+		return m.Text
+	}
+
+	return m.Text + "  " + m.Description
+}
+
+// unquotePath undoes quoting and escaping of spaces that users tend to type
+// out of the shell habit.
+func unquotePath(text string) string {
+	if len(text) >= 1 && (text[0] == '"' || text[0] == '\'') {
+		text = strings.TrimSuffix(text[1:], text[:1])
+	}
+
+	return strings.Replace(text, `\ `, " ", -1)
+}
+
+// expandPath unquotes the path typed by the user and expands the home
+// directory, so it is resolved the same way by hints, validation and
+// decryption.
+func expandPath(text string) (string, error) {
+	return homedir.Expand(unquotePath(strings.TrimSpace(text)))
+}
+
+// keystoreHint completes directory paths, remembering scans of directories
+// suggested.
+//
+// Directories containing keyfiles are marked with the number of accounts
+// and followed by a hint for each account, so choosing one fills in both the
+// keystore and the account. Hidden directories are only listed if the typed
+// name starts with a dot, just like shells do, while well-known keystore
+// locations matching the text are suggested regardless.
+func keystoreHint(scans *keystoreScans) HintProvider {
+	return func(ctx context.Context, text string) []Hint {
+		return keystoreHints(ctx, scans, text)
+	}
+}
+
+func keystoreHints(ctx context.Context, scans *keystoreScans, text string) []Hint {
+	text, err := expandPath(text)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	seen := map[string]bool{}
+	addDir := func(dir string) {
+		dir = strings.TrimSuffix(dir, string(filepath.Separator)) + string(filepath.Separator)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if text != "" {
+		parent, prefix := "", text
+		if id := strings.LastIndex(text, string(filepath.Separator)); id >= 0 {
+			parent, prefix = text[:id+1], text[id+1:]
+		}
+
+		readDir := parent
+		if readDir == "" {
+			readDir = "."
+		}
+
+		// Reading the directory by hand rather than globbing keeps paths
+		// containing glob metacharacters, like brackets, intact.
+		files, _ := ioutil.ReadDir(readDir)
+		for _, file := range files {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			name := file.Name()
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+				continue
+			}
+
+			if isDir(filepath.Join(readDir, name)) {
+				addDir(parent + name)
+			}
+		}
+	}
+
+	for _, path := range wallet.KnownKeystorePaths {
+		path, err := homedir.Expand(path)
+		if err != nil || !strings.HasPrefix(path, text) {
+			continue
+		}
+
+		if isDir(path) {
+			addDir(path)
+		}
+	}
+
+	hints := make([]Hint, 0, len(dirs))
+	for _, dir := range dirs {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		addrs, _ := scans.Scan(dir)
+		if len(addrs) == 0 {
+			hints = append(hints, Hint{Text: dir})
+			continue
+		}
+
+		hints = append(hints, Hint{Text: dir, Description: accountCount(len(addrs))})
+		for _, addr := range addrs {
+			hints = append(hints, Hint{Text: dir, Description: "→ " + addr.Hex(), Value: addr})
+		}
+	}
+
+	return hints
+}
+
+// addressHint completes the given addresses, described by their names from
+// the address book, so they can be found by name as well.
+func addressHint(book *addressbook.Book, addrs []common.Address) HintProvider {
+	return func(ctx context.Context, text string) []Hint {
+		hints := make([]Hint, 0, len(addrs))
+		for _, addr := range addrs {
+			hints = append(hints, Hint{Text: addr.Hex(), Description: book.Name(addr), Value: addr})
+		}
+
+		return hints
+	}
+}
+
+func isDir(path string) bool {
+	fileInfo, err := os.Stat(path)
+	return err == nil && fileInfo.IsDir()
+}
+
+func accountCount(count int) string {
+	if count == 1 {
+		return "keystore, 1 account"
+	}
+
+	return fmt.Sprintf("keystore, %d accounts", count)
+}
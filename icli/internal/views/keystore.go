@@ -0,0 +1,105 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/3Hren/sonmui/icli/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type keystoreScan struct {
+	addrs []common.Address
+	err   error
+}
+
+// keystoreScans remembers accounts found in keystore directories, so fields
+// can be validated on the UI thread without touching the filesystem.
+//
+// Directories are scanned in the background, either for completion or when
+// the keystore is loaded explicitly, which also refreshes the result.
+type keystoreScans struct {
+	mu    sync.Mutex
+	scans map[string]keystoreScan
+}
+
+func newKeystoreScans() *keystoreScans {
+	return &keystoreScans{
+		scans: map[string]keystoreScan{},
+	}
+}
+
+// Scan checks that the path, as typed by the user, is a directory containing
+// keyfiles and returns their accounts, remembering the result.
+//
+// This reads the directory, so it should not be called from the UI thread.
+func (m *keystoreScans) Scan(text string) ([]common.Address, error) {
+	key, err := keystoreKey(text)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := scanKeystore(key)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scans[key] = keystoreScan{addrs: addrs, err: err}
+
+	return addrs, err
+}
+
+// Lookup returns the result of the last scan of the path, if any.
+func (m *keystoreScans) Lookup(text string) (keystoreScan, bool) {
+	key, err := keystoreKey(text)
+	if err != nil {
+		return keystoreScan{err: err}, true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	scan, ok := m.scans[key]
+	return scan, ok
+}
+
+// keystoreKey resolves the path typed by the user, so paths differing in
+// quoting or trailing separators share the scan.
+func keystoreKey(text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("keystore path is required")
+	}
+
+	path, err := expandPath(text)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Clean(path), nil
+}
+
+func scanKeystore(path string) ([]common.Address, error) {
+	fileInfo, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return nil, fmt.Errorf("directory %s does not exist", path)
+	case err != nil:
+		return nil, err
+	case !fileInfo.IsDir():
+		return nil, fmt.Errorf("%s is not a directory", path)
+	}
+
+	addrs, err := wallet.ScanKeystore(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %v", err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no keyfiles found in %s", path)
+	}
+
+	return addrs, nil
+}
@@ -1,50 +1,23 @@
 package views
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/3Hren/sonmui/icli/internal/interactions"
+	"github.com/3Hren/sonmui/icli/internal/addressbook"
+	"github.com/3Hren/sonmui/icli/internal/keymap"
 	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/signer"
 	"github.com/3Hren/sonmui/icli/internal/widgets"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/marcusolsson/tui-go"
-	"github.com/mitchellh/go-homedir"
-	"github.com/sonm-io/core/accounts"
 )
 
-func filesystemHint(text string) []string {
-	if len(text) == 0 {
-		return nil
-	}
-
-	text, err := homedir.Expand(text)
-	if err != nil {
-		return nil
-	}
-
-	matches, err := filepath.Glob(text + "*")
-	if err != nil {
-		return nil
-	}
-
-	dirs := make([]string, 0, len(matches))
-	for _, match := range matches {
-		fileInfo, err := os.Lstat(match)
-		if err != nil {
-			continue
-		}
-
-		if fileInfo.IsDir() {
-			dirs = append(dirs, match+"/")
-		}
-	}
-
-	return dirs
-}
-
 type StyledBox struct {
 	Style string
 	*tui.Box
@@ -57,54 +30,86 @@ func (s *StyledBox) Draw(p *tui.Painter) {
 	})
 }
 
+// HintProvider returns completion hints for the given text.
+//
+// Providers are called outside of the UI thread, so they are free to hit the
+// filesystem or the network, but should give up once the context is
+// canceled, which happens when suggestions are requested again.
+type HintProvider func(ctx context.Context, text string) []Hint
+
+const (
+	// maxSuggestions limits the number of suggestions shown after filtering.
+	maxSuggestions = 100
+	// visibleSuggestions is the height of the suggestion list, the rest of
+	// suggestions is scrolled.
+	visibleSuggestions = 8
+)
+
+// EditHint is an entry with completion suggestions.
+//
+// Suggestions are requested asynchronously on the "complete" action and
+// shown below the entry. Requesting completion again focuses the list, where
+// typing narrows suggestions down using fuzzy matching.
 type EditHint struct {
 	*tui.Box
 
+	router                 *mp.Router
 	entry                  *tui.Entry
 	suggestionsFilterEntry *tui.Entry
 	suggestionsList        *widgets.List
-	suggestions            []string
-	onSubmit               func(entry *tui.Entry)
-	OnHintRequested        func(text string) []string
+	// suggestions are all hints returned by the provider for hintText.
+	suggestions []Hint
+	// filtered are hints matching the current filter, including those not
+	// shown due to the limit.
+	filtered []Hint
+	// shown are hints in the order of the suggestion list items.
+	shown      []Hint
+	hintText   string
+	hintID     int
+	cancelHint context.CancelFunc
+	onSubmit   func(entry *tui.Entry)
+	onHint     func(hint Hint)
+	keys       *keymap.Keymap
+
+	OnHintRequested HintProvider
 }
 
-func NewEditHint() *EditHint {
+func NewEditHint(router *mp.Router) *EditHint {
 	entry := tui.NewEntry()
 	box := tui.NewVBox(entry)
 
 	suggestionsList := widgets.NewList()
-	suggestionsList.OnSelectionChanged(func(list *tui.List) {
-		if list.Length() == 0 || list.Selected() == -1 {
-			return
-		}
-		entry.SetText(list.SelectedItem())
-	})
-
+	suggestionsList.SetMaxVisible(visibleSuggestions)
 	m := &EditHint{
 		Box:                    box,
+		router:                 router,
 		entry:                  entry,
 		suggestionsFilterEntry: tui.NewEntry(),
 		suggestionsList:        suggestionsList,
 	}
 
-	suggestionsList.OnItemActivated(func(hints *tui.List) {
-		if hints.Length() == 0 {
+	suggestionsList.OnSelectionChanged(func(list *tui.List) {
+		if list.Selected() < 0 || list.Selected() >= len(m.shown) {
+			return
+		}
+		entry.SetText(m.shown[list.Selected()].Text)
+	})
+	suggestionsList.OnItemActivated(func(list *tui.List) {
+		if list.Selected() < 0 || list.Selected() >= len(m.shown) {
 			return
 		}
 
-		entry.SetText(hints.SelectedItem())
-		m.suggestions = nil
-		m.suggestionsFilterEntry.SetText("")
-		hints.RemoveItems()
-		m.hideSuggestions()
+		hint := m.shown[list.Selected()]
+		m.resetSuggestions()
 
-		hints.SetFocused(false)
+		suggestionsList.SetFocused(false)
 		entry.SetFocused(true)
+		m.activateHint(hint)
 	})
 
 	entry.OnSubmit(func(entry *tui.Entry) {
-		suggestionsList.RemoveItems()
-		m.hideSuggestions()
+		m.cancelHintRequest()
+		m.resetSuggestions()
 
 		if m.onSubmit != nil {
 			m.onSubmit(entry)
@@ -123,7 +128,7 @@ func (m *EditHint) showFilterForm() {
 }
 
 func (m *EditHint) hideFilterForm() {
-	for m.Length() != 3 {
+	if m.Length() != 3 {
 		return
 	}
 
@@ -131,8 +136,8 @@ func (m *EditHint) hideFilterForm() {
 }
 
 func (m *EditHint) showSuggestions() {
-	for m.Length() != 1 {
-		panic("")
+	if m.Length() != 1 {
+		return
 	}
 
 	m.Append(m.suggestionsList)
@@ -144,88 +149,192 @@ func (m *EditHint) hideSuggestions() {
 	}
 }
 
+// resetSuggestions hides and forgets all suggestions.
+func (m *EditHint) resetSuggestions() {
+	m.suggestions = nil
+	m.filtered = nil
+	m.shown = nil
+	m.hintText = ""
+	m.suggestionsFilterEntry.SetText("")
+	m.suggestionsList.RemoveItems()
+	m.hideSuggestions()
+}
+
+func (m *EditHint) SetKeymap(keys *keymap.Keymap) {
+	m.keys = keys
+}
+
 func (m *EditHint) SetFocused(v bool) {
 	m.entry.SetFocused(v)
 }
 
+func (m *EditHint) Text() string {
+	return m.entry.Text()
+}
+
+func (m *EditHint) SetText(text string) {
+	m.entry.SetText(text)
+}
+
+// CanComplete makes the entry keep <Tab> when placed into a FocusBox, as
+// long as there is a hint provider.
+func (m *EditHint) CanComplete() bool {
+	return m.OnHintRequested != nil
+}
+
+// requestHints asks the provider for suggestions in the background,
+// canceling the previous request if any.
+func (m *EditHint) requestHints() {
+	if m.OnHintRequested == nil {
+		return
+	}
+
+	m.cancelHintRequest()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelHint = cancel
+	m.hintID++
+
+	id := m.hintID
+	text := m.entry.Text()
+	provider := m.OnHintRequested
+
+	go func() {
+		suggestions := provider(ctx, text)
+
+		m.router.Execute(func() {
+			// Results are stale if requested again or the text is changed.
+			if id != m.hintID || ctx.Err() != nil || m.entry.Text() != text {
+				return
+			}
+
+			m.cancelHintRequest()
+			m.setSuggestions(text, suggestions)
+		})
+	}()
+}
+
+func (m *EditHint) cancelHintRequest() {
+	if m.cancelHint != nil {
+		m.cancelHint()
+		m.cancelHint = nil
+	}
+}
+
+func (m *EditHint) setSuggestions(text string, suggestions []Hint) {
+	switch len(suggestions) {
+	case 0:
+		m.resetSuggestions()
+	case 1:
+		m.resetSuggestions()
+		m.entry.SetText(suggestions[0].Text)
+		m.activateHint(suggestions[0])
+	default:
+		m.suggestions = suggestions
+		m.hintText = text
+		m.suggestionsFilterEntry.SetText("")
+		m.hideFilterForm()
+		m.showFilteredSuggestions(suggestions, filterHints("", suggestions))
+		m.showSuggestions()
+	}
+}
+
+func (m *EditHint) activateHint(hint Hint) {
+	if m.onHint != nil {
+		m.onHint(hint)
+	}
+}
+
+// filterHints fuzzy-matches hints by their labels, so descriptions can be
+// searched too.
+func filterHints(filter string, hints []Hint) []widgets.FuzzyMatch {
+	labels := make([]string, 0, len(hints))
+	for _, hint := range hints {
+		labels = append(labels, hint.label())
+	}
+
+	return widgets.FuzzyFilter(filter, labels)
+}
+
+func (m *EditHint) showFilteredSuggestions(candidates []Hint, matches []widgets.FuzzyMatch) {
+	m.filtered = make([]Hint, 0, len(matches))
+	for _, match := range matches {
+		m.filtered = append(m.filtered, candidates[match.Index])
+	}
+
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
+
+	m.shown = m.filtered[:len(matches)]
+	m.suggestionsList.RemoveItems()
+	for id, match := range matches {
+		m.suggestionsList.AddItems(match.Item)
+		if len(match.Positions) > 0 {
+			m.suggestionsList.SetHighlights(id, match.Positions)
+		}
+	}
+
+	if m.suggestionsList.IsFocused() && len(matches) > 0 {
+		m.suggestionsList.Select(0)
+	}
+}
+
+// setFilter narrows suggestions down to those matching the filter. While
+// the filter grows only suggestions matching its previous value need to be
+// checked.
+func (m *EditHint) setFilter(filter string) {
+	candidates := m.suggestions
+	if prev := m.suggestionsFilterEntry.Text(); prev != "" && strings.HasPrefix(filter, prev) {
+		candidates = m.filtered
+	}
+
+	m.suggestionsFilterEntry.SetText(filter)
+	m.showFilteredSuggestions(candidates, filterHints(filter, candidates))
+}
+
 func (m *EditHint) OnKeyEvent(ev tui.KeyEvent) {
 	switch ev.Key {
 	case tui.KeyRune:
 		if m.suggestionsList.IsFocused() {
 			m.showFilterForm()
-			m.suggestionsFilterEntry.SetText(fmt.Sprintf("%s%c", m.suggestionsFilterEntry.Text(), ev.Rune))
-
-			var filtered []string
-			for _, hint := range m.suggestions {
-				if strings.Contains(hint, m.suggestionsFilterEntry.Text()) {
-					filtered = append(filtered, hint)
-				}
-			}
-			m.suggestionsList.ReplaceItems(filtered...)
-
-			if len(filtered) > 0 {
-				m.suggestionsList.Select(0)
-			}
-
+			m.setFilter(m.suggestionsFilterEntry.Text() + string(ev.Rune))
 			return
 		}
 	case tui.KeyBackspace, tui.KeyBackspace2:
 		if m.suggestionsList.IsFocused() {
-			if len(m.suggestionsFilterEntry.Text()) == 1 {
-				m.hideFilterForm()
-			}
+			filter := []rune(m.suggestionsFilterEntry.Text())
 
-			if len(m.suggestionsFilterEntry.Text()) == 0 {
-				m.suggestions = nil
+			switch len(filter) {
+			case 0:
+				m.resetSuggestions()
 				m.suggestionsList.SetFocused(false)
-				m.suggestionsList.RemoveItems()
-				m.hideSuggestions()
 				m.entry.SetFocused(true)
-			} else {
-				m.suggestionsFilterEntry.SetText(m.suggestionsFilterEntry.Text()[:len(m.suggestionsFilterEntry.Text())-1])
-
-				var filtered []string
-				for _, hint := range m.suggestions {
-					if strings.Contains(hint, m.suggestionsFilterEntry.Text()) {
-						filtered = append(filtered, hint)
-					}
-				}
-				m.suggestionsList.ReplaceItems(filtered...)
-
-				if len(filtered) > 0 {
-					m.suggestionsList.Select(0)
-				}
-			}
-		}
-	case tui.KeyTab:
-		if m.entry.IsFocused() {
-			suggestions := m.OnHintRequested(m.entry.Text())
-
-			switch len(suggestions) {
-			case 0:
-				return
 			case 1:
-				m.entry.SetText(suggestions[0])
+				m.hideFilterForm()
+				fallthrough
 			default:
-				if m.suggestionsList.Length() != 0 {
-					m.entry.SetFocused(false)
-					m.suggestionsList.SetFocused(true)
-					m.showSuggestions()
-
-					m.suggestions = suggestions
-					m.suggestionsList.ReplaceItems(suggestions...)
-					m.suggestionsList.Select(0)
-				} else {
-					// On first "Tab" we just show available hints without focusing.
-					m.suggestions = suggestions
-					m.suggestionsList.ReplaceItems(suggestions...)
-				}
+				m.setFilter(string(filter[:len(filter)-1]))
 			}
 
 			return
 		}
 	}
 
+	if m.keys.Match(keymap.Complete, ev) && m.entry.IsFocused() {
+		if m.suggestionsList.Length() > 0 && m.hintText == m.entry.Text() {
+			// Suggestions are already shown, so the second request focuses
+			// them.
+			m.entry.SetFocused(false)
+			m.suggestionsList.SetFocused(true)
+			m.suggestionsList.Select(0)
+		} else {
+			m.requestHints()
+		}
+
+		return
+	}
+
 	m.Box.OnKeyEvent(ev)
 }
 
@@ -233,65 +342,40 @@ func (m *EditHint) OnSubmit(fn func(entry *tui.Entry)) {
 	m.onSubmit = fn
 }
 
+// OnHintActivated sets the callback called after a hint is chosen and its
+// text is put into the entry.
+func (m *EditHint) OnHintActivated(fn func(hint Hint)) {
+	m.onHint = fn
+}
+
 type LoginView struct {
 	*tui.Box
 
-	keystoreLabel       *tui.Label
-	keystoreEdit        *EditHint
-	accountLabel        *tui.Label
-	accountEdit         *EditHint
-	passwordLabel       *tui.Label
-	passwordEdit        *tui.Entry
-	accountUnlockButton *tui.Button
-	cancelButton        *tui.Button
-
-	onKeyEvent func(ev tui.KeyEvent) bool
+	keystoreEdit *EditHint
+	accountEdit  *EditHint
+	form         *widgets.Form
+	scans        *keystoreScans
 }
 
-func NewLoginView() *LoginView {
-	keystoreLabel := tui.NewLabel("Keystore Path:")
-	keystoreLabel.SetStyleName("bold")
-
-	keystoreEdit := NewEditHint()
+func NewLoginView(router *mp.Router) *LoginView {
+	keystoreEdit := NewEditHint(router)
 	keystoreEdit.SetSizePolicy(tui.Expanding, tui.Preferred)
-	keystoreEdit.OnHintRequested = filesystemHint
-
-	accountLabel := tui.NewLabel("Account:")
-	accountLabel.SetStyleName("bold")
+	scans := newKeystoreScans()
+	keystoreEdit.OnHintRequested = keystoreHint(scans)
 
-	accountEdit := NewEditHint()
+	accountEdit := NewEditHint(router)
 	accountEdit.SetSizePolicy(tui.Expanding, tui.Preferred)
 
-	passwordLabel := tui.NewLabel("Password:")
-	passwordLabel.SetStyleName("bold")
-
-	passwordEdit := tui.NewEntry()
-	passwordEdit.SetEchoMode(tui.EchoModePassword)
-	passwordEdit.SetSizePolicy(tui.Expanding, tui.Preferred)
-
-	accountUnlockButton := tui.NewButton("[Unlock]")
-	cancelButton := tui.NewButton("[Cancel]")
+	form := widgets.NewForm(router, []widgets.FormField{
+		{Name: "keystore", Label: "Keystore Path", Kind: widgets.TextField, Required: true, Input: keystoreEdit},
+		{Name: "account", Label: "Account", Kind: widgets.AddressField, Required: true, Input: accountEdit},
+		{Name: "password", Label: "Password", Kind: widgets.PasswordField},
+	}, "[Unlock]", "[Cancel]")
 
 	box := tui.NewVBox(
 		tui.NewPadder(50, 0, tui.NewSpacer()),
 		tui.NewHBox(tui.NewSpacer(), tui.NewLabel("Please Sign In"), tui.NewSpacer()),
-		tui.NewHBox(
-			keystoreLabel,
-			tui.NewPadder(1, 0, keystoreEdit),
-		),
-		tui.NewHBox(
-			accountLabel,
-			tui.NewPadder(7, 0, accountEdit),
-		),
-		tui.NewHBox(
-			passwordLabel,
-			tui.NewPadder(6, 0, passwordEdit),
-		),
-		tui.NewHBox(
-			tui.NewSpacer(),
-			tui.NewPadder(1, 0, accountUnlockButton),
-			tui.NewPadder(1, 0, cancelButton),
-		),
+		form,
 	)
 	box.SetBorder(true)
 
@@ -307,39 +391,70 @@ func NewLoginView() *LoginView {
 	)
 
 	return &LoginView{
-		Box:                 window,
-		keystoreLabel:       keystoreLabel,
-		keystoreEdit:        keystoreEdit,
-		accountLabel:        accountLabel,
-		accountEdit:         accountEdit,
-		passwordLabel:       passwordLabel,
-		passwordEdit:        passwordEdit,
-		accountUnlockButton: accountUnlockButton,
-		cancelButton:        cancelButton,
+		Box:          window,
+		keystoreEdit: keystoreEdit,
+		accountEdit:  accountEdit,
+		form:         form,
+		scans:        scans,
 	}
 }
 
-func (m *LoginView) OnKeyEvent(ev tui.KeyEvent) {
-	if m.onKeyEvent != nil && m.onKeyEvent(ev) {
-		return
+func (m *LoginView) SetFocused(v bool) {
+	m.form.SetFocused(v)
+}
+
+const (
+	loginHint = "Specify directory with keystore. <Tab> for completion, <Enter> for submitting"
+
+	// loginRetryDelay is the delay after the first failed password attempt,
+	// which doubles with each next failure up to loginMaxRetryDelay.
+	loginRetryDelay    = time.Second
+	loginMaxRetryDelay = 30 * time.Second
+)
+
+// validateAccount checks that the account is one of the given accounts.
+func validateAccount(account common.Address, addrs []common.Address) error {
+	for _, addr := range addrs {
+		if addr == account {
+			return nil
+		}
 	}
 
-	m.Box.OnKeyEvent(ev)
+	return fmt.Errorf("account %s is not found in the keystore", account.Hex())
 }
 
-func (m *LoginView) SetFocused(v bool) {
-	m.keystoreEdit.SetFocused(v)
-}
+// retryDelay returns the delay before the next password attempt is allowed
+// after the given number of consecutive failures.
+func retryDelay(failures int) time.Duration {
+	delay := loginRetryDelay
+	for id := 1; id < failures && delay < loginMaxRetryDelay; id++ {
+		delay *= 2
+	}
+
+	if delay > loginMaxRetryDelay {
+		delay = loginMaxRetryDelay
+	}
 
-type LabeledWidget struct {
-	Label  *tui.Label
-	Widget tui.Widget
+	return delay
 }
 
 type LoginController struct {
-	view *LoginView
-
-	focusController *interactions.FocusController
+	view      *LoginView
+	navigator *widgets.Navigator
+	book      *addressbook.Book
+	router    *mp.Router
+
+	// failures counts consecutive failed password attempts. Unlocking is
+	// refused until retryAt after each failure.
+	failures int
+	retryAt  time.Time
+	// unlockID identifies the current unlock attempt, so results of attempts
+	// made before the view was reset are dropped.
+	unlockID  int
+	unlocking bool
+	// loadID identifies the last keystore load, so only its result is
+	// applied.
+	loadID int
 
 	// Signals
 
@@ -347,125 +462,265 @@ type LoginController struct {
 	OnCancel   *mp.Signal
 }
 
-func NewLoginController(view *LoginView, router *mp.Router) *LoginController {
+func NewLoginController(view *LoginView, navigator *widgets.Navigator, keys *keymap.Keymap, book *addressbook.Book, router *mp.Router) *LoginController {
+	form := view.form
+	form.SetKeymap(keys)
+	view.keystoreEdit.SetKeymap(keys)
+	view.accountEdit.SetKeymap(keys)
+
+	help := keymap.NewContext()
+	help.RegisterFor(view.keystoreEdit, keymap.Complete, "Complete the keystore path and its accounts")
+	help.RegisterFor(view.accountEdit, keymap.Complete, "Show accounts of the keystore")
+	help.Register(keymap.NextFocus, "")
+	help.Register(keymap.PrevFocus, "")
+	form.RegisterInputs(help)
+	navigator.SetContext(view, help)
+
 	m := &LoginController{
-		view:            view,
-		focusController: interactions.NewFocusController(interactions.NewFocusChain(view.keystoreEdit, view.cancelButton)),
+		view:      view,
+		navigator: navigator,
+		book:      book,
+		router:    router,
 
 		OnUnlocked: router.NewSignal(),
 		OnCancel:   router.NewSignal(),
 	}
 
-	view.keystoreEdit.OnSubmit(func(entry *tui.Entry) {
-		keystore, err := accounts.NewMultiKeystore(accounts.NewKeystoreConfig(entry.Text()), accounts.NewStaticPassPhraser(""))
-		if err != nil {
-			return
+	// Validators run on the UI thread each time the focus leaves a field, so
+	// they only look up keystores scanned in the background and never change
+	// the state of the screen. Keystores not scanned yet are checked once
+	// loaded, which is required to enable the account anyway.
+	form.SetValidator("keystore", func(v interface{}) error {
+		scan, _ := view.scans.Lookup(v.(string))
+		return scan.err
+	})
+	form.SetValidator("account", func(v interface{}) error {
+		scan, ok := view.scans.Lookup(form.Text("keystore"))
+		switch {
+		case !ok:
+			return errors.New("press <Enter> on the keystore path to load its accounts")
+		case scan.err != nil:
+			return errors.New("enter a valid keystore to check the account against")
 		}
 
-		if len(keystore.List()) > 0 {
-			m.setInvalidAccountState()
+		return validateAccount(v.(common.Address), scan.addrs)
+	})
+	// Hints are requested in the background, so the keystore path is read on
+	// the UI thread.
+	view.accountEdit.OnHintRequested = func(ctx context.Context, text string) []Hint {
+		keystore := make(chan string, 1)
+		router.Execute(func() { keystore <- form.Text("keystore") })
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case path := <-keystore:
+			addrs, err := view.scans.Scan(path)
+			if err != nil {
+				return nil
+			}
+
+			return addressHint(m.book, addrs)(ctx, text)
 		}
+	}
 
-		router.Execute(func() {
+	view.keystoreEdit.OnSubmit(func(entry *tui.Entry) {
+		m.loadKeystore(func() {
 			if entry.IsFocused() {
-				m.focusController.FocusNextWidget()
-				m.HighlightActiveWidget()
-				view.accountEdit.OnHintRequested = func(text string) []string {
-					accounts := make([]string, 0, len(keystore.List()))
-					for _, account := range keystore.List() {
-						accounts = append(accounts, account.Address.Hex())
-					}
-
-					return accounts
-				}
+				form.FocusNext()
 			}
 		})
 	})
 
+	// Keystore hints pointing to an account fill both fields in, leaving
+	// only the password to be typed.
+	view.keystoreEdit.OnHintActivated(func(hint Hint) {
+		account, ok := hint.Value.(common.Address)
+		if !ok {
+			return
+		}
+
+		m.loadKeystore(func() {
+			form.SetText("account", account.Hex())
+			form.SetError("account", nil)
+			m.setValidAccountState()
+			form.Focus("password")
+		})
+	})
+
 	view.accountEdit.OnSubmit(func(entry *tui.Entry) {
+		if _, err := form.ValidateField("account"); err != nil {
+			return
+		}
+
 		m.setValidAccountState()
 
 		router.Execute(func() {
 			if entry.IsFocused() {
-				// todo: error check
-				m.focusController.FocusNextWidget()
-				m.HighlightActiveWidget()
+				form.FocusNext()
 			}
 		})
 	})
 
-	view.passwordEdit.OnSubmit(func(entry *tui.Entry) {
-		m.focusController.FocusNextWidget()
-		m.HighlightActiveWidget()
+	form.OnSubmit.Connect(func(v interface{}) {
+		m.unlock(v.(widgets.FormValues))
+	})
+	// Leaving the view either way, by the cancel button or by going back,
+	// abandons the current attempt.
+	navigator.OnRemoved(view, m.abandonUnlock)
+	form.OnCancel.Connect(func(interface{}) {
+		navigator.Pop()
+		m.OnCancel.Emit(struct{}{})
 	})
 
-	m.view.onKeyEvent = func(ev tui.KeyEvent) bool {
-		switch ev.Key {
-		case tui.KeyTab:
-			switch m.focusController.FocusedWidget {
-			case m.view.keystoreEdit, m.view.accountEdit:
-			default:
-				m.focusController.FocusNextWidget()
-				m.HighlightActiveWidget()
-				return true
+	return m
+}
+
+// loadKeystore scans the entered keystore in the background and, if it is
+// valid, enables the account and calls the function. Errors are shown below
+// the keystore path. Results of loads superseded by another one, by editing
+// the path or by resetting the view are dropped.
+func (m *LoginController) loadKeystore(fn func()) {
+	m.loadID++
+	id := m.loadID
+	text := m.view.form.Text("keystore")
+
+	go func() {
+		m.view.scans.Scan(text)
+
+		m.router.Execute(func() {
+			if id != m.loadID || m.view.form.Text("keystore") != text {
+				return
+			}
+			if _, err := m.view.form.ValidateField("keystore"); err != nil {
+				return
 			}
-		}
 
-		return false
+			m.setInvalidAccountState()
+			fn()
+		})
+	}()
+}
+
+// unlock decrypts the key in the background, since decryption is
+// deliberately slow.
+func (m *LoginController) unlock(values widgets.FormValues) {
+	if m.unlocking {
+		return
 	}
 
-	view.accountUnlockButton.OnActivated(func(*tui.Button) {
-		keystore, err := accounts.NewMultiKeystore(accounts.NewKeystoreConfig(view.keystoreEdit.entry.Text()), accounts.NewStaticPassPhraser(""))
-		if err != nil {
-			return
-		}
+	if wait := time.Until(m.retryAt); wait > 0 {
+		m.view.form.SetError("password", fmt.Errorf("too many failed attempts, try again in %v", wait.Round(time.Second)))
+		return
+	}
 
-		account, err := keystore.GetKeyWithPass(common.HexToAddress(view.accountEdit.entry.Text()), view.passwordEdit.Text())
-		if err != nil {
-			return
-		}
+	m.view.form.SetError("password", nil)
 
-		m.OnUnlocked.Emit(account)
-	})
-	view.cancelButton.OnActivated(func(*tui.Button) {
-		m.OnCancel.Emit(struct{}{})
-	})
+	path, err := expandPath(values.String("keystore"))
+	if err != nil {
+		m.view.form.SetError("keystore", err)
+		return
+	}
 
-	return m
+	m.unlocking = true
+	id := m.unlockID
+	account := values.Address("account")
+	password := values.String("password")
+
+	go func() {
+		key, err := signer.LoadKey(path, account, password)
+
+		m.router.Execute(func() {
+			if id != m.unlockID {
+				signer.Zero(key)
+				return
+			}
+
+			m.unlocking = false
+			m.onUnlocked(key, err)
+		})
+	}()
 }
 
-func (m *LoginController) HighlightActiveWidget() {
-	availablePairs := []LabeledWidget{
-		{Label: m.view.keystoreLabel, Widget: m.view.keystoreEdit},
-		{Label: m.view.accountLabel, Widget: m.view.accountEdit},
-		{Label: m.view.passwordLabel, Widget: m.view.passwordEdit},
-	}
+func (m *LoginController) onUnlocked(key *ecdsa.PrivateKey, err error) {
+	form := m.view.form
 
-	for _, widget := range availablePairs {
-		style := "normal"
-		if widget.Widget.IsFocused() {
-			style = "highlight"
+	if err != nil {
+		if err != keystore.ErrDecrypt {
+			form.SetError("password", fmt.Errorf("failed to decrypt the key: %v", err))
+			return
 		}
-		widget.Label.SetStyleName(style)
+
+		m.failures++
+		delay := retryDelay(m.failures)
+		m.retryAt = time.Now().Add(delay)
+
+		form.SetText("password", "")
+		form.SetError("password", fmt.Errorf("wrong password, attempt %d failed; try again in %v", m.failures, delay))
+		return
 	}
+
+	m.failures = 0
+	m.retryAt = time.Time{}
+	form.SetText("password", "")
+	form.SetError("password", nil)
+
+	m.OnUnlocked.Emit(key)
 }
 
 func (m *LoginController) SetInvalidAccountPathState() {
-	m.focusController = interactions.NewFocusController(interactions.NewFocusChain(m.view.keystoreEdit, m.view.cancelButton))
-	m.HighlightActiveWidget()
+	m.setEnabled(false, false)
 }
 
 func (m *LoginController) setInvalidAccountState() {
-	m.focusController = interactions.NewFocusController(interactions.NewFocusChain(m.view.keystoreEdit, m.view.accountEdit, m.view.cancelButton))
-	m.HighlightActiveWidget()
+	m.setEnabled(true, false)
 }
 
 func (m *LoginController) setValidAccountState() {
-	m.focusController = interactions.NewFocusController(interactions.NewFocusChain(m.view.accountEdit, m.view.passwordEdit, m.view.accountUnlockButton, m.view.cancelButton, m.view.keystoreEdit))
-	m.HighlightActiveWidget()
+	m.setEnabled(true, true)
+}
+
+// setEnabled enables fields following the keystore path as soon as they can
+// be filled in.
+func (m *LoginController) setEnabled(account, password bool) {
+	m.view.form.SetFieldEnabled("account", account)
+	m.view.form.SetFieldEnabled("password", password)
+	m.view.form.SetSubmitEnabled(password)
+}
+
+func (m *LoginController) abandonUnlock() {
+	m.unlockID++
+	m.unlocking = false
 }
 
 func (m *LoginController) Reset() {
+	m.abandonUnlock()
+	m.loadID++
+
 	m.SetInvalidAccountPathState()
-	m.view.SetFocused(true)
+	m.view.form.Reset()
+}
+
+// SetKeystorePath fills the keystore path in as if it was submitted by the
+// user.
+func (m *LoginController) SetKeystorePath(path string) {
+	m.view.keystoreEdit.SetText(path)
+	m.view.keystoreEdit.onSubmit(m.view.keystoreEdit.entry)
+}
+
+// KeystorePath returns the keystore path entered by the user, unquoted and
+// with the home directory expanded.
+func (m *LoginController) KeystorePath() string {
+	path, err := expandPath(m.view.keystoreEdit.Text())
+	if err != nil {
+		return m.view.keystoreEdit.Text()
+	}
+
+	return path
+}
+
+// Show resets the view and opens it on top of the current screen.
+func (m *LoginController) Show() {
+	m.Reset()
+	m.navigator.Push(m.view, loginHint)
 }
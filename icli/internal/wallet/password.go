@@ -0,0 +1,78 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinPasswordLength = 8
+	// Passwords at least this long are accepted regardless of characters
+	// they consist of, which allows passphrases made of plain words.
+	MinPassphraseLength = 16
+	minCharClasses      = 3
+)
+
+var ErrPasswordMismatch = errors.New("passwords do not match")
+
+var commonPasswords = map[string]struct{}{
+	"password":  {},
+	"password1": {},
+	"12345678":  {},
+	"123456789": {},
+	"qwertyui":  {},
+	"qwerty123": {},
+	"iloveyou":  {},
+	"sunshine":  {},
+	"11111111":  {},
+	"abc12345":  {},
+}
+
+// CheckPassword verifies that the password is strong enough to protect a
+// newly created key.
+func CheckPassword(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+
+	if _, ok := commonPasswords[password]; ok {
+		return errors.New("password is too common")
+	}
+
+	if length >= MinPassphraseLength {
+		return nil
+	}
+
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	if lower+upper+digit+other < minCharClasses {
+		return fmt.Errorf("password must contain at least %d of: lowercase letters, uppercase letters, digits and symbols, or be at least %d characters long", minCharClasses, MinPassphraseLength)
+	}
+
+	return nil
+}
+
+// CheckNewPassword verifies the password strength and that it was confirmed
+// correctly.
+func CheckNewPassword(password, confirmation string) error {
+	if password != confirmation {
+		return ErrPasswordMismatch
+	}
+
+	return CheckPassword(password)
+}
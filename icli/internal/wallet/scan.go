@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// maxKeyfileSize bounds files read while scanning, since keyfiles are a
+	// few hundred bytes and directories may contain anything.
+	maxKeyfileSize = 64 * 1024
+)
+
+// KnownKeystorePaths lists keystore locations used by SONM and popular
+// Ethereum clients on different platforms.
+var KnownKeystorePaths = []string{
+	DefaultKeystorePath,
+	"~/.ethereum/keystore",
+	"~/.ethereum/rinkeby/keystore",
+	"~/.ethereum/testnet/keystore",
+	"~/Library/Ethereum/keystore",
+	"~/AppData/Roaming/Ethereum/keystore",
+	"~/.local/share/io.parity.ethereum/keys/ethereum",
+	"~/Library/Application Support/io.parity.ethereum/keys/ethereum",
+	"~/AppData/Roaming/Parity/Ethereum/keys/ethereum",
+}
+
+// ScanKeystore returns addresses of keyfiles found in the directory without
+// decrypting them. Files that are not keyfiles are silently skipped, so a
+// directory without keyfiles results in an empty list.
+func ScanKeystore(dir string) ([]common.Address, error) {
+	dir, err := homedir.Expand(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []common.Address
+	for _, file := range files {
+		if !file.Mode().IsRegular() || file.Size() > maxKeyfileSize {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			continue
+		}
+
+		var keyfile struct {
+			Address string          `json:"address"`
+			Crypto  json.RawMessage `json:"crypto"`
+		}
+		if err := json.Unmarshal(content, &keyfile); err != nil {
+			continue
+		}
+		if keyfile.Crypto == nil || !common.IsHexAddress(keyfile.Address) {
+			continue
+		}
+
+		addrs = append(addrs, common.HexToAddress(keyfile.Address))
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Hex() < addrs[j].Hex()
+	})
+
+	return addrs, nil
+}
@@ -0,0 +1,152 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	DefaultKeystorePath = "~/.sonm/keystore"
+)
+
+func openKeystore(dir string) (*keystore.KeyStore, error) {
+	dir, err := homedir.Expand(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP), nil
+}
+
+// Create generates a new key and stores it in the keystore directory,
+// encrypted with the given password.
+func Create(dir, password string) (common.Address, error) {
+	ks, err := openKeystore(dir)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	account, err := ks.NewAccount(password)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return account.Address, nil
+}
+
+// ImportKey stores the given private key in the keystore directory,
+// encrypted with the given password.
+func ImportKey(dir string, key *ecdsa.PrivateKey, password string) (common.Address, error) {
+	ks, err := openKeystore(dir)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	account, err := ks.ImportECDSA(key, password)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return account.Address, nil
+}
+
+// ImportHexKey is like ImportKey, but accepts hex-encoded private key with
+// optional "0x" prefix.
+func ImportHexKey(dir, hexKey, password string) (common.Address, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid private key: %v", err)
+	}
+
+	return ImportKey(dir, key, password)
+}
+
+// ImportKeyfile decrypts the given JSON keyfile with its password and stores
+// it in the keystore directory, encrypted with the new password.
+func ImportKeyfile(dir string, keyJSON []byte, password, newPassword string) (common.Address, error) {
+	ks, err := openKeystore(dir)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	account, err := ks.Import(keyJSON, password, newPassword)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return account.Address, nil
+}
+
+// IsKeyfile checks whether the given content looks like a JSON keyfile
+// rather than a raw hex-encoded key.
+func IsKeyfile(content []byte) bool {
+	var v map[string]interface{}
+	return json.Unmarshal(content, &v) == nil
+}
+
+// Export decrypts the key of the given account and returns it as a JSON
+// keyfile encrypted with the new password.
+func Export(dir string, account common.Address, password, newPassword string) ([]byte, error) {
+	ks, err := openKeystore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := ks.Find(accounts.Account{Address: account})
+	if err != nil {
+		return nil, fmt.Errorf("account %s is not found in %s: %v", account.Hex(), dir, err)
+	}
+
+	return ks.Export(found, password, newPassword)
+}
+
+// ExportFile is like Export, but writes the keyfile to the given path, which
+// must not exist.
+func ExportFile(dir string, account common.Address, password, newPassword, path string) error {
+	keyJSON, err := Export(dir, account, password, newPassword)
+	if err != nil {
+		return err
+	}
+
+	path, err = homedir.Expand(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(keyJSON); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// ReadKeyfile reads the keyfile or the file containing raw hex-encoded key.
+func ReadKeyfile(path string) ([]byte, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadFile(path)
+}
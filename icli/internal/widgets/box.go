@@ -2,34 +2,89 @@ package widgets
 
 import (
 	"github.com/3Hren/sonmui/icli/internal/interactions"
+	"github.com/3Hren/sonmui/icli/internal/keymap"
 	"github.com/marcusolsson/tui-go"
 )
 
+// FocusBox is a box that moves the focus between its widgets using the given
+// focus controller.
 //
-//
-// Note that this box consumes all <Tab> events.
+// Note that this box consumes all events bound to "next-focus", "prev-focus",
+// "focus-up" and "focus-down" actions, i.e. <Tab>, <Shift+Tab>, <Alt+Up> and
+// <Alt+Down> by default. The latter two move the focus spatially between
+// widgets wrapped into interactions.Anchor. Inputs implementing Completer
+// keep chords bound to the "complete" action, since it shares <Tab>.
 type FocusBox struct {
 	*tui.Box
 
 	controller *interactions.FocusController
+	keys       *keymap.Keymap
+}
+
+// Completer is implemented by inputs handling the "complete" action.
+type Completer interface {
+	// CanComplete checks whether completion is available right now.
+	CanComplete() bool
 }
 
 func NewFocusBox(box *tui.Box, controller *interactions.FocusController) *FocusBox {
-	return &FocusBox{Box: box, controller: controller}
+	m := &FocusBox{Box: box}
+	m.SetFocusController(controller)
+
+	return m
 }
 
 func (m *FocusBox) SetFocusController(controller *interactions.FocusController) {
 	m.controller = controller
+	if controller != nil {
+		controller.SetRoot(m.Box)
+	}
+}
+
+func (m *FocusBox) SetKeymap(keys *keymap.Keymap) {
+	m.keys = keys
+}
+
+// SetFocused passes the focus to the widget currently focused by the
+// controller, which allows to restore the focus after the box was hidden.
+func (m *FocusBox) SetFocused(focused bool) {
+	if m.controller == nil || m.controller.FocusedWidget == nil {
+		return
+	}
+
+	m.controller.FocusedWidget.SetFocused(focused)
 }
 
 func (m *FocusBox) OnKeyEvent(ev tui.KeyEvent) {
+	if completer, ok := m.focusedWidget().(Completer); ok && completer.CanComplete() && m.keys.Match(keymap.Complete, ev) {
+		m.Box.OnKeyEvent(ev)
+		return
+	}
+
 	if m.IsFocused() {
-		switch ev.Key {
-		case tui.KeyTab:
+		switch {
+		case m.keys.Match(keymap.NextFocus, ev):
 			m.controller.FocusNextWidget()
 			return
+		case m.keys.Match(keymap.PrevFocus, ev):
+			m.controller.FocusPrevWidget()
+			return
+		case m.keys.Match(keymap.FocusUp, ev):
+			m.controller.FocusDirection(interactions.Up)
+			return
+		case m.keys.Match(keymap.FocusDown, ev):
+			m.controller.FocusDirection(interactions.Down)
+			return
 		}
 	}
 
 	m.Box.OnKeyEvent(ev)
 }
+
+func (m *FocusBox) focusedWidget() tui.Widget {
+	if m.controller == nil {
+		return nil
+	}
+
+	return m.controller.FocusedWidget
+}
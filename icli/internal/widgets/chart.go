@@ -0,0 +1,316 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"unicode/utf8"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	// chartWidth is the preferred width of charts.
+	chartWidth = 24
+)
+
+var (
+	// sparkRunes are block elements from the lowest to the highest one.
+	sparkRunes = []rune("▁▂▃▄▅▆▇█")
+	// barRunes are block elements from the thinnest to the widest one.
+	barRunes = []rune("▏▎▍▌▋▊▉█")
+)
+
+// Sparkline draws the most recent values fitting its width as a single row
+// of blocks, whose heights are scaled between the minimum and the maximum
+// shown, using the "chart" style.
+type Sparkline struct {
+	tui.WidgetBase
+
+	values []float64
+}
+
+func NewSparkline() *Sparkline {
+	return &Sparkline{}
+}
+
+func (m *Sparkline) SetValues(values []float64) {
+	m.values = values
+}
+
+func (m *Sparkline) SizeHint() image.Point {
+	return image.Pt(chartWidth, 1)
+}
+
+func (m *Sparkline) Draw(painter *tui.Painter) {
+	values := tail(m.values, m.Size().X)
+	lo, hi := bounds(values)
+
+	painter.WithStyle("chart", func(painter *tui.Painter) {
+		for x, value := range values {
+			level := scale(value, lo, hi, len(sparkRunes))
+			painter.DrawRune(x, 0, sparkRunes[level])
+		}
+	})
+}
+
+// Bar is a single bar of BarChart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart draws a horizontal bar for each value, scaled to the maximum
+// one, with labels on the left and values on the right.
+type BarChart struct {
+	tui.WidgetBase
+
+	bars   []Bar
+	format func(value float64) string
+}
+
+func NewBarChart() *BarChart {
+	return &BarChart{
+		format: func(value float64) string { return fmt.Sprintf("%g", value) },
+	}
+}
+
+func (m *BarChart) SetBars(bars ...Bar) {
+	m.bars = bars
+}
+
+// SetFormatter sets the function formatting values shown after bars.
+func (m *BarChart) SetFormatter(fn func(value float64) string) {
+	m.format = fn
+}
+
+func (m *BarChart) SizeHint() image.Point {
+	labelWidth, valueWidth := m.widths()
+	return image.Pt(labelWidth+chartWidth+valueWidth, len(m.bars))
+}
+
+func (m *BarChart) Draw(painter *tui.Painter) {
+	labelWidth, valueWidth := m.widths()
+	barWidth := m.Size().X - labelWidth - valueWidth
+	if barWidth <= 0 {
+		return
+	}
+
+	highest := 0.0
+	for _, bar := range m.bars {
+		highest = math.Max(highest, bar.Value)
+	}
+
+	for y, bar := range m.bars {
+		painter.WithStyle("chart.axis", func(painter *tui.Painter) {
+			painter.DrawText(0, y, bar.Label)
+		})
+
+		// Bars are measured in eighths of a cell.
+		length := 0
+		if highest > 0 && bar.Value > 0 {
+			length = int(math.Round(bar.Value / highest * float64(barWidth*len(barRunes))))
+		}
+
+		painter.WithStyle("chart", func(painter *tui.Painter) {
+			x := labelWidth
+			for ; length >= len(barRunes); length -= len(barRunes) {
+				painter.DrawRune(x, y, barRunes[len(barRunes)-1])
+				x++
+			}
+			if length > 0 {
+				painter.DrawRune(x, y, barRunes[length-1])
+			}
+		})
+
+		painter.DrawText(labelWidth+barWidth+1, y, m.format(bar.Value))
+	}
+}
+
+// widths returns widths of the label and the value columns, including the
+// spacing around bars.
+func (m *BarChart) widths() (int, int) {
+	labelWidth, valueWidth := 0, 0
+	for _, bar := range m.bars {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(bar.Label)+1)
+		valueWidth = max(valueWidth, utf8.RuneCountInString(m.format(bar.Value))+1)
+	}
+
+	return labelWidth, valueWidth
+}
+
+// LinePlot draws the most recent values as a line using braille characters,
+// each cell holding 2x4 dots, so the plot has twice as many points as its
+// width and four levels per row.
+//
+// If a formatter is set, the maximum and the minimum values shown are
+// labeled on the left using the "chart.axis" style.
+type LinePlot struct {
+	tui.WidgetBase
+
+	values []float64
+	height int
+	format func(value float64) string
+}
+
+// NewLinePlot constructs a plot with the given height in rows.
+func NewLinePlot(height int) *LinePlot {
+	return &LinePlot{
+		height: height,
+	}
+}
+
+func (m *LinePlot) SetValues(values []float64) {
+	m.values = values
+}
+
+// SetFormatter sets the function formatting axis labels.
+func (m *LinePlot) SetFormatter(fn func(value float64) string) {
+	m.format = fn
+}
+
+func (m *LinePlot) SizeHint() image.Point {
+	return image.Pt(chartWidth, m.height)
+}
+
+func (m *LinePlot) Draw(painter *tui.Painter) {
+	size := m.Size()
+	if size.Y <= 0 {
+		return
+	}
+
+	// Labels of the widest window possible decide the width of the axis, then
+	// bounds are computed over values fitting the rest. The axis only grows if
+	// those labels are wider, so they always fit.
+	axisWidth := 0
+	if m.format != nil && len(m.values) > 0 {
+		axisWidth = m.axisWidth(bounds(tail(m.values, 2*size.X)))
+	}
+
+	width, values := 0, []float64(nil)
+	lo, hi := 0.0, 0.0
+	for {
+		width = size.X - axisWidth
+		if width <= 0 {
+			return
+		}
+
+		values = tail(m.values, 2*width)
+		lo, hi = bounds(values)
+		if axisWidth == 0 || m.axisWidth(lo, hi) <= axisWidth {
+			break
+		}
+
+		axisWidth = m.axisWidth(lo, hi)
+	}
+
+	if axisWidth > 0 {
+		top, bottom := m.format(hi), m.format(lo)
+		painter.WithStyle("chart.axis", func(painter *tui.Painter) {
+			painter.DrawText(axisWidth-1-utf8.RuneCountInString(top), 0, top)
+			if size.Y > 1 {
+				painter.DrawText(axisWidth-1-utf8.RuneCountInString(bottom), size.Y-1, bottom)
+			}
+		})
+	}
+
+	levels := 4 * size.Y
+	cells := make([][]rune, size.Y)
+	for y := range cells {
+		cells[y] = make([]rune, width)
+		for x := range cells[y] {
+			cells[y][x] = brailleBase
+		}
+	}
+
+	prev := -1
+	for x, value := range values {
+		level := scale(value, lo, hi, levels)
+
+		// Vertical gaps between neighbours are filled to keep the line
+		// continuous.
+		from, to := level, level
+		if prev >= 0 {
+			if prev < level {
+				from = prev + 1
+			} else if prev > level {
+				to = prev - 1
+			}
+		}
+		for l := from; l <= to; l++ {
+			y := levels - 1 - l
+			cells[y/4][x/2] |= brailleDots[x%2][y%4]
+		}
+
+		prev = level
+	}
+
+	painter.WithStyle("chart", func(painter *tui.Painter) {
+		for y, row := range cells {
+			for x, cell := range row {
+				if cell != brailleBase {
+					painter.DrawRune(axisWidth+x, y, cell)
+				}
+			}
+		}
+	})
+}
+
+// axisWidth returns the width of labels of the given bounds, including the
+// spacing before the plot.
+func (m *LinePlot) axisWidth(lo, hi float64) int {
+	return 1 + max(utf8.RuneCountInString(m.format(hi)), utf8.RuneCountInString(m.format(lo)))
+}
+
+const brailleBase = '⠀'
+
+// brailleDots maps dot positions within a cell, by column and row, to bits
+// of braille patterns.
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// tail returns at most the given number of the last values.
+func tail(values []float64, n int) []float64 {
+	if n < 0 {
+		n = 0
+	}
+	if len(values) > n {
+		return values[len(values)-n:]
+	}
+
+	return values
+}
+
+func bounds(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	lo, hi := values[0], values[0]
+	for _, value := range values[1:] {
+		lo = math.Min(lo, value)
+		hi = math.Max(hi, value)
+	}
+
+	return lo, hi
+}
+
+// scale maps the value between lo and hi to one of the given number of
+// levels. Values of a flat series are drawn at the lowest level.
+func scale(value, lo, hi float64, levels int) int {
+	if hi <= lo {
+		return 0
+	}
+
+	level := int(math.Round((value - lo) / (hi - lo) * float64(levels-1)))
+	if level < 0 {
+		return 0
+	}
+	if level >= levels {
+		return levels - 1
+	}
+
+	return level
+}
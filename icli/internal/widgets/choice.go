@@ -0,0 +1,127 @@
+package widgets
+
+import (
+	"image"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// choiceInput selects one of the choices using <Left> and <Right> arrows or
+// <Space>, which cycles through them.
+type choiceInput struct {
+	tui.WidgetBase
+
+	choices  []string
+	selected int
+}
+
+func newChoiceInput(choices []string) *choiceInput {
+	return &choiceInput{choices: choices}
+}
+
+func (m *choiceInput) Text() string {
+	if m.selected < 0 || m.selected >= len(m.choices) {
+		return ""
+	}
+
+	return m.choices[m.selected]
+}
+
+// SetText selects the given choice, the first one is selected if there is
+// no such choice.
+func (m *choiceInput) SetText(text string) {
+	m.selected = 0
+	for id, choice := range m.choices {
+		if choice == text {
+			m.selected = id
+		}
+	}
+}
+
+func (m *choiceInput) SizeHint() image.Point {
+	width := 0
+	for _, choice := range m.choices {
+		if w := utf8.RuneCountInString(choice); w > width {
+			width = w
+		}
+	}
+
+	return image.Pt(width+4, 1)
+}
+
+func (m *choiceInput) Draw(painter *tui.Painter) {
+	style := "entry"
+	if m.IsFocused() {
+		style += ".focused"
+	}
+
+	painter.WithStyle(style, func(painter *tui.Painter) {
+		painter.FillRect(0, 0, m.Size().X, 1)
+		painter.DrawText(0, 0, "◂ "+m.Text()+" ▸")
+	})
+}
+
+func (m *choiceInput) OnKeyEvent(ev tui.KeyEvent) {
+	if !m.IsFocused() || len(m.choices) == 0 {
+		return
+	}
+
+	switch {
+	case ev.Key == tui.KeyLeft:
+		m.selected = (m.selected + len(m.choices) - 1) % len(m.choices)
+	case ev.Key == tui.KeyRight, ev.Key == tui.KeyRune && ev.Rune == ' ':
+		m.selected = (m.selected + 1) % len(m.choices)
+	}
+}
+
+// checkInput is a checkbox toggled using <Space>. Its text is either "true"
+// or "false".
+type checkInput struct {
+	tui.WidgetBase
+
+	checked bool
+}
+
+func newCheckInput() *checkInput {
+	return &checkInput{}
+}
+
+func (m *checkInput) Text() string {
+	return strconv.FormatBool(m.checked)
+}
+
+func (m *checkInput) SetText(text string) {
+	m.checked = text == "true"
+}
+
+func (m *checkInput) SizeHint() image.Point {
+	return image.Pt(3, 1)
+}
+
+func (m *checkInput) Draw(painter *tui.Painter) {
+	style := "entry"
+	if m.IsFocused() {
+		style += ".focused"
+	}
+
+	mark := "[ ]"
+	if m.checked {
+		mark = "[x]"
+	}
+
+	painter.WithStyle(style, func(painter *tui.Painter) {
+		painter.DrawText(0, 0, mark)
+	})
+}
+
+func (m *checkInput) OnKeyEvent(ev tui.KeyEvent) {
+	if !m.IsFocused() {
+		return
+	}
+
+	if ev.Key == tui.KeyRune && ev.Rune == ' ' {
+		m.checked = !m.checked
+	}
+}
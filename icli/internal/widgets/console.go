@@ -0,0 +1,106 @@
+package widgets
+
+import (
+	"sync"
+
+	"github.com/3Hren/sonmui/icli/internal/logging"
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	defaultConsoleHeight = 8
+	consoleTimeFormat    = "15:04:05"
+)
+
+var consoleLevelStyles = map[logging.Level]string{
+	logging.DebugLevel: "normal",
+	logging.InfoLevel:  "ok",
+	logging.WarnLevel:  "warn",
+	logging.ErrorLevel: "error",
+}
+
+// LogConsole is a pane showing the most recent log entries, newest at the
+// bottom.
+//
+// Entries are received from the logger on arbitrary goroutines and are
+// batched before being routed to the UI thread, so logging from UI slots is
+// safe.
+type LogConsole struct {
+	*tui.Box
+
+	lines   []*tui.Label
+	entries []logging.Entry
+
+	mu        sync.Mutex
+	pending   []logging.Entry
+	scheduled bool
+	router    *mp.Router
+}
+
+func NewLogConsole(log *logging.Logger, router *mp.Router) *LogConsole {
+	linesBox := tui.NewVBox()
+	lines := make([]*tui.Label, defaultConsoleHeight)
+	for id := range lines {
+		lines[id] = tui.NewLabel("")
+		linesBox.Append(lines[id])
+	}
+
+	box := tui.NewVBox(linesBox)
+	box.SetBorder(true)
+	box.SetTitle("Log")
+	box.SetSizePolicy(tui.Expanding, tui.Minimum)
+
+	m := &LogConsole{
+		Box:    box,
+		lines:  lines,
+		router: router,
+	}
+
+	for _, entry := range log.Recent() {
+		m.append(entry)
+	}
+	log.Subscribe(m.enqueue)
+
+	return m
+}
+
+func (m *LogConsole) enqueue(entry logging.Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending = append(m.pending, entry)
+	if m.scheduled {
+		return
+	}
+
+	m.scheduled = true
+	go m.router.Execute(m.flush)
+}
+
+func (m *LogConsole) flush() {
+	m.mu.Lock()
+	entries := m.pending
+	m.pending = nil
+	m.scheduled = false
+	m.mu.Unlock()
+
+	for _, entry := range entries {
+		m.append(entry)
+	}
+}
+
+func (m *LogConsole) append(entry logging.Entry) {
+	m.entries = append(m.entries, entry)
+	if len(m.entries) > len(m.lines) {
+		m.entries = m.entries[len(m.entries)-len(m.lines):]
+	}
+
+	// Keep the newest entry at the bottom, leaving empty lines on top.
+	offset := len(m.lines) - len(m.entries)
+	for id, entry := range m.entries {
+		line := m.lines[offset+id]
+		line.SetText(entry.Time.Format(consoleTimeFormat) + " " + entry.Level.String() + " " + entry.Text())
+		line.SetStyleName(consoleLevelStyles[entry.Level])
+	}
+}
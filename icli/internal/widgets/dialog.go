@@ -0,0 +1,322 @@
+package widgets
+
+import (
+	"image"
+	"strings"
+
+	"github.com/3Hren/sonmui/icli/internal/interactions"
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	defaultDialogWidth = 48
+	// maxDialogChoices is the number of choices shown at once, the rest are
+	// scrolled.
+	maxDialogChoices = 10
+)
+
+// DialogResult describes how a dialog was closed. It is emitted through the
+// dialog's OnResult signal.
+type DialogResult struct {
+	// Accepted is true when the dialog was closed using the affirmative
+	// action, i.e. [OK] button or <Enter>.
+	Accepted bool
+	// Text contains the entered text for input dialogs.
+	Text string
+	// Choice contains the index of the selected option for multiple-choice
+	// dialogs and -1 otherwise.
+	Choice int
+}
+
+// Dialog is a modal window that is drawn over the current root widget by the
+// DialogStack.
+//
+// Note that this box consumes all events bound to "back" and the focus
+// actions handled by FocusBox while visible.
+type Dialog struct {
+	*FocusBox
+
+	entry   *Entry
+	choices *List
+	stack   *DialogStack
+
+	OnResult *mp.Signal
+}
+
+// newDialog constructs a dialog with an optional content widget placed
+// between the text and buttons. The cancel button is omitted when its text is
+// empty.
+func newDialog(router *mp.Router, title, text string, content tui.Widget, focusContent bool, okText, cancelText string) *Dialog {
+	textLabel := tui.NewLabel(text)
+
+	okButton := tui.NewButton(okText)
+	cancelButton := tui.NewButton(cancelText)
+
+	buttonsBox := tui.NewHBox(tui.NewSpacer(), tui.NewPadder(1, 0, okButton))
+	if cancelText != "" {
+		buttonsBox.Append(cancelButton)
+	}
+
+	box := tui.NewVBox(
+		tui.NewPadder(defaultDialogWidth/2, 0, tui.NewSpacer()),
+		tui.NewPadder(1, 1, textLabel),
+	)
+	if content != nil {
+		box.Append(tui.NewPadder(1, 0, content))
+	}
+	box.Append(tui.NewPadder(1, 1, buttonsBox))
+	box.SetBorder(true)
+	box.SetTitle(title)
+
+	focusScope := interactions.NewFocusScope()
+	if content != nil && focusContent {
+		focusScope.AddWidget(content)
+	}
+	focusScope.AddWidget(okButton)
+	if cancelText != "" {
+		focusScope.AddWidget(cancelButton)
+	}
+
+	focusController := interactions.NewFocusController(focusScope, router)
+	focusController.FocusDefaultWidget()
+
+	m := &Dialog{
+		FocusBox: NewFocusBox(box, focusController),
+
+		OnResult: router.NewSignal(),
+	}
+
+	okButton.OnActivated(func(*tui.Button) { m.Accept() })
+	cancelButton.OnActivated(func(*tui.Button) { m.Reject() })
+
+	return m
+}
+
+// NewConfirmDialog constructs an OK/Cancel dialog.
+func NewConfirmDialog(router *mp.Router, title, text string) *Dialog {
+	return newDialog(router, title, text, nil, false, "[OK]", "[Cancel]")
+}
+
+// NewInputDialog constructs a dialog with a single text entry. The entered
+// text is returned in DialogResult.Text.
+func NewInputDialog(router *mp.Router, title, text string, echoMode tui.EchoMode) *Dialog {
+	entry := NewEntry()
+	entry.SetEchoMode(echoMode)
+	entry.SetSizeHint(image.Point{X: defaultDialogWidth - 2, Y: 1})
+
+	m := newDialog(router, title, text, entry, true, "[OK]", "[Cancel]")
+	m.entry = entry
+
+	entry.OnSubmit(func(*tui.Entry) { m.Accept() })
+
+	return m
+}
+
+// NewChoiceDialog constructs a multiple-choice dialog. The index of the
+// selected option is returned in DialogResult.Choice.
+func NewChoiceDialog(router *mp.Router, title, text string, choices ...string) *Dialog {
+	choicesList := NewList()
+	choicesList.AddItems(choices...)
+
+	scrollView := NewScrollView(choicesList)
+	scrollView.SetMaxVisible(maxDialogChoices)
+
+	m := newDialog(router, title, text, scrollView, true, "[Select]", "[Cancel]")
+	m.choices = choicesList
+
+	choicesList.OnItemActivated(func(*tui.List) { m.Accept() })
+
+	return m
+}
+
+// NewHelpDialog constructs a dialog listing key chords bound to the given
+// actions.
+func NewHelpDialog(router *mp.Router, title string, keys *keymap.Keymap, bindings []keymap.Binding) *Dialog {
+	chordsBox := tui.NewVBox()
+	descriptionsBox := tui.NewVBox()
+
+	for _, binding := range bindings {
+		chords := keys.Chords(binding.Action)
+		if len(chords) == 0 {
+			continue
+		}
+
+		chordsLabel := tui.NewLabel(strings.Join(chords, ", "))
+		chordsLabel.SetStyleName("bold")
+
+		chordsBox.Append(chordsLabel)
+		descriptionsBox.Append(tui.NewLabel(binding.Description))
+	}
+
+	content := tui.NewHBox(tui.NewPadder(1, 0, chordsBox), descriptionsBox, tui.NewSpacer())
+
+	return newDialog(router, title, "Available key bindings:", content, false, "[Close]", "")
+}
+
+// SetText fills the entry of the input dialog in, which is useful to propose
+// a default value.
+func (m *Dialog) SetText(text string) {
+	if m.entry != nil {
+		m.entry.SetText(text)
+	}
+}
+
+// Accept closes the dialog emitting an affirmative result.
+func (m *Dialog) Accept() {
+	result := &DialogResult{Accepted: true, Choice: -1}
+	if m.entry != nil {
+		result.Text = m.entry.Text()
+	}
+	if m.choices != nil {
+		result.Choice = m.choices.Selected()
+		if result.Choice == -1 {
+			result.Choice = m.choices.selectedItemBefore
+		}
+	}
+
+	m.close(result)
+}
+
+// Reject closes the dialog emitting a negative result.
+func (m *Dialog) Reject() {
+	m.close(&DialogResult{Choice: -1})
+}
+
+func (m *Dialog) close(result *DialogResult) {
+	if m.stack != nil {
+		m.stack.remove(m)
+	}
+
+	m.OnResult.Emit(result)
+}
+
+func (m *Dialog) OnKeyEvent(ev tui.KeyEvent) {
+	if m.keys.Match(keymap.Back, ev) {
+		m.Reject()
+		return
+	}
+
+	m.FocusBox.OnKeyEvent(ev)
+}
+
+// DialogStack is a root widget that draws modal dialogs over the wrapped root
+// widget.
+//
+// While there is at least one dialog opened all key events are routed to the
+// topmost one.
+type DialogStack struct {
+	tui.WidgetBase
+
+	root    tui.Widget
+	dialogs []*Dialog
+	keys    *keymap.Keymap
+}
+
+func NewDialogStack(root tui.Widget) *DialogStack {
+	return &DialogStack{root: root}
+}
+
+// SetRoot replaces the widget drawn under dialogs.
+func (m *DialogStack) SetRoot(root tui.Widget) {
+	m.root = root
+}
+
+// SetKeymap sets the keymap used by all dialogs pushed afterwards.
+func (m *DialogStack) SetKeymap(keys *keymap.Keymap) {
+	m.keys = keys
+}
+
+// Push opens the given dialog on top of the others.
+func (m *DialogStack) Push(dialog *Dialog) {
+	dialog.stack = m
+	dialog.SetKeymap(m.keys)
+	m.dialogs = append(m.dialogs, dialog)
+}
+
+// Pop closes the topmost dialog, rejecting it.
+func (m *DialogStack) Pop() {
+	if len(m.dialogs) == 0 {
+		return
+	}
+
+	m.dialogs[len(m.dialogs)-1].Reject()
+}
+
+// Length returns the number of opened dialogs.
+func (m *DialogStack) Length() int {
+	return len(m.dialogs)
+}
+
+func (m *DialogStack) remove(dialog *Dialog) {
+	for id, d := range m.dialogs {
+		if d == dialog {
+			m.dialogs = append(m.dialogs[:id], m.dialogs[id+1:]...)
+			break
+		}
+	}
+
+	dialog.stack = nil
+}
+
+func (m *DialogStack) Draw(painter *tui.Painter) {
+	m.root.Draw(painter)
+
+	for _, dialog := range m.dialogs {
+		size := dialog.Size()
+		x := (m.Size().X - size.X) / 2
+		y := (m.Size().Y - size.Y) / 2
+
+		painter.Translate(x, y)
+		painter.WithMask(image.Rectangle{Max: size}, func(painter *tui.Painter) {
+			painter.WithStyle("dialog", func(painter *tui.Painter) {
+				painter.FillRect(0, 0, size.X, size.Y)
+				dialog.Draw(painter)
+			})
+		})
+		painter.Restore()
+	}
+}
+
+func (m *DialogStack) Resize(size image.Point) {
+	m.WidgetBase.Resize(size)
+	m.root.Resize(size)
+
+	for _, dialog := range m.dialogs {
+		hint := dialog.SizeHint()
+		if hint.X > size.X {
+			hint.X = size.X
+		}
+		if hint.Y > size.Y {
+			hint.Y = size.Y
+		}
+
+		dialog.Resize(hint)
+	}
+}
+
+func (m *DialogStack) MinSizeHint() image.Point {
+	return m.root.MinSizeHint()
+}
+
+func (m *DialogStack) SizeHint() image.Point {
+	return m.root.SizeHint()
+}
+
+func (m *DialogStack) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	return m.root.SizePolicy()
+}
+
+func (m *DialogStack) IsFocused() bool {
+	return true
+}
+
+func (m *DialogStack) OnKeyEvent(ev tui.KeyEvent) {
+	if len(m.dialogs) > 0 {
+		m.dialogs[len(m.dialogs)-1].OnKeyEvent(ev)
+		return
+	}
+
+	m.root.OnKeyEvent(ev)
+}
@@ -0,0 +1,68 @@
+package widgets
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Field decorates an input widget with a validation message shown below it.
+// The message takes no space while the field is valid.
+type Field struct {
+	*tui.Box
+
+	widget tui.Widget
+	label  *tui.Label
+	err    error
+}
+
+func NewField(widget tui.Widget) *Field {
+	label := tui.NewLabel("")
+	label.SetStyleName("error")
+
+	return &Field{
+		Box:    tui.NewVBox(widget),
+		widget: widget,
+		label:  label,
+	}
+}
+
+// SetError shows the error below the widget, nil hides it.
+func (m *Field) SetError(err error) {
+	m.err = err
+
+	if err == nil {
+		if m.Length() > 1 {
+			m.Remove(1)
+		}
+		return
+	}
+
+	m.label.SetText(capitalize(err.Error()))
+	if m.Length() == 1 {
+		m.Append(m.label)
+	}
+}
+
+// Error returns the error shown or nil.
+func (m *Field) Error() error {
+	return m.err
+}
+
+// SetFocused passes the focus to the decorated widget, so the field can be
+// put into focus chains instead of the widget.
+func (m *Field) SetFocused(v bool) {
+	m.widget.SetFocused(v)
+}
+
+// capitalize makes error messages, which are lowercase by convention, look
+// like sentences.
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if r == utf8.RuneError {
+		return text
+	}
+
+	return string(unicode.ToUpper(r)) + text[size:]
+}
@@ -0,0 +1,520 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/3Hren/sonmui/icli/internal/interactions"
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/units"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/marcusolsson/tui-go"
+)
+
+// FieldKind determines the input of a form field and the type of its value.
+type FieldKind int
+
+const (
+	// TextField is a plain entry with a string value.
+	TextField FieldKind = iota
+	// PasswordField is an entry hiding its text with a string value.
+	PasswordField
+	// AddressField is an entry with a common.Address value.
+	AddressField
+	// AmountField is an AmountEntry with a *big.Int value in wei.
+	AmountField
+	// PriceField is a PriceEntry with a *big.Int value in USD per second
+	// scaled by 10^18.
+	PriceField
+	// DurationField is a DurationEntry with a time.Duration value.
+	DurationField
+	// NumberField is a NumberEntry with an int64 value between Min and Max.
+	NumberField
+	// EnumField selects one of Choices with the int value being its index.
+	EnumField
+	// BoolField is a checkbox with a bool value.
+	BoolField
+)
+
+const (
+	// formEntryWidth is the preferred width of form entries.
+	formEntryWidth = 32
+)
+
+// TextInput is a widget holding text, like tui.Entry. All form inputs are
+// text inputs, values are parsed from their text according to the kind.
+type TextInput interface {
+	tui.Widget
+	Text() string
+	SetText(text string)
+}
+
+// FormField describes a single field of a Form.
+type FormField struct {
+	// Name identifies the field in FormValues.
+	Name string
+	// Label is shown to the left of the input and in error messages.
+	Label string
+	Kind  FieldKind
+	// Text is the initial text of the input. For EnumField it is the
+	// initially selected choice and for BoolField either "true" or "false".
+	Text string
+	// Choices are options of EnumField.
+	Choices []string
+	// Min and Max bound values of NumberField. Both zero means any int64.
+	Min int64
+	Max int64
+	// Required fields must not be left empty.
+	Required bool
+	// ReadOnly fields are shown as labels and can't be focused.
+	ReadOnly bool
+	// Input replaces the default input of text-based kinds, for example
+	// with an entry having completion. The owner of the input is
+	// responsible for handling its submission.
+	Input TextInput
+	// Validate checks the parsed value, which is never called for empty
+	// optional fields.
+	Validate func(value interface{}) error
+}
+
+// FormValues maps field names to their parsed values.
+type FormValues map[string]interface{}
+
+func (m FormValues) String(name string) string {
+	v, _ := m[name].(string)
+	return v
+}
+
+func (m FormValues) Address(name string) common.Address {
+	v, _ := m[name].(common.Address)
+	return v
+}
+
+// Amount returns the amount in wei or nil if the field was left empty.
+func (m FormValues) Amount(name string) *big.Int {
+	v, _ := m[name].(*big.Int)
+	return v
+}
+
+// Price returns the price in USD per second scaled by 10^18 or nil if the
+// field was left empty.
+func (m FormValues) Price(name string) *big.Int {
+	v, _ := m[name].(*big.Int)
+	return v
+}
+
+func (m FormValues) Duration(name string) time.Duration {
+	v, _ := m[name].(time.Duration)
+	return v
+}
+
+func (m FormValues) Number(name string) int64 {
+	v, _ := m[name].(int64)
+	return v
+}
+
+// Choice returns the index of the selected choice.
+func (m FormValues) Choice(name string) int {
+	v, _ := m[name].(int)
+	return v
+}
+
+func (m FormValues) Bool(name string) bool {
+	v, _ := m[name].(bool)
+	return v
+}
+
+type formField struct {
+	FormField
+
+	label *tui.Label
+	input TextInput
+	field *Field
+}
+
+// Form lays out labeled inputs described by a schema followed by submit and
+// cancel buttons, moving the focus between them and highlighting the label
+// of the focused input.
+//
+// Submitting validates all enabled fields, showing errors below invalid
+// ones, and emits FormValues through the OnSubmit signal if there are none.
+// Fields are also validated once the focus leaves them. Pressing <Enter> in
+// an entry moves the focus to the next field or submits the form from the
+// last one.
+type Form struct {
+	*FocusBox
+
+	fields       []*formField
+	controller   *interactions.FocusController
+	submitButton *tui.Button
+	cancelButton *tui.Button
+	// resetSerial is the serial of the last focus change made before the
+	// form was reset. Fields left before are not validated.
+	resetSerial uint64
+
+	OnSubmit *mp.Signal
+	OnCancel *mp.Signal
+}
+
+// NewForm constructs a form with the given fields. The cancel button is
+// omitted when its text is empty.
+func NewForm(router *mp.Router, fields []FormField, submitText, cancelText string) *Form {
+	labelWidth := 0
+	for _, field := range fields {
+		if width := utf8.RuneCountInString(field.Label) + 1; width > labelWidth {
+			labelWidth = width
+		}
+	}
+
+	submitButton := tui.NewButton(submitText)
+	cancelButton := tui.NewButton(cancelText)
+
+	focusScope := interactions.NewFocusScope()
+	box := tui.NewVBox()
+
+	m := &Form{
+		submitButton: submitButton,
+		cancelButton: cancelButton,
+
+		OnSubmit: router.NewSignal(),
+		OnCancel: router.NewSignal(),
+	}
+
+	for _, spec := range fields {
+		field := &formField{
+			FormField: spec,
+			label:     tui.NewLabel(fmt.Sprintf("%-*s", labelWidth, spec.Label+":")),
+			input:     newFormInput(spec),
+		}
+		field.label.SetStyleName("normal")
+		field.input.SetText(spec.Text)
+		field.field = NewField(interactions.NewAnchor(field.input))
+
+		if !spec.ReadOnly {
+			focusScope.AddWidget(field.input)
+		}
+
+		if entry, ok := field.input.(submitter); ok && spec.Input == nil {
+			entry.OnSubmit(func(*tui.Entry) { m.next(field) })
+		}
+
+		box.Append(tui.NewHBox(field.label, tui.NewPadder(1, 0, field.field)))
+		m.fields = append(m.fields, field)
+	}
+
+	buttonsBox := tui.NewHBox(tui.NewSpacer(), tui.NewPadder(1, 0, interactions.NewAnchor(submitButton)))
+	focusScope.AddWidget(submitButton)
+	if cancelText != "" {
+		buttonsBox.Append(tui.NewPadder(1, 0, interactions.NewAnchor(cancelButton)))
+		focusScope.AddWidget(cancelButton)
+	}
+	box.Append(tui.NewPadder(0, 1, buttonsBox))
+
+	m.controller = interactions.NewFocusController(focusScope, router)
+	m.FocusBox = NewFocusBox(box, m.controller)
+
+	m.controller.OnFocusChanged.Connect(func(v interface{}) {
+		change := v.(interactions.FocusChange)
+		if field := m.fieldOf(change.From); field != nil && change.Serial > m.resetSerial && strings.TrimSpace(field.input.Text()) != "" {
+			m.validate(field)
+		}
+
+		m.highlight()
+	})
+
+	submitButton.OnActivated(func(*tui.Button) { m.Submit() })
+	cancelButton.OnActivated(func(*tui.Button) { m.OnCancel.Emit(struct{}{}) })
+
+	return m
+}
+
+func newFormInput(spec FormField) TextInput {
+	if spec.ReadOnly {
+		return tui.NewLabel("")
+	}
+
+	switch spec.Kind {
+	case EnumField:
+		return newChoiceInput(spec.Choices)
+	case BoolField:
+		return newCheckInput()
+	}
+
+	if spec.Input != nil {
+		return spec.Input
+	}
+
+	switch spec.Kind {
+	case AmountField:
+		return NewAmountEntry()
+	case PriceField:
+		return NewPriceEntry()
+	case DurationField:
+		return NewDurationEntry()
+	case NumberField:
+		min, max := spec.bounds()
+		return NewNumberEntry(min, max)
+	}
+
+	entry := NewEntry()
+	entry.SetSizeHint(image.Pt(formEntryWidth, 1))
+	entry.SetSizePolicy(tui.Expanding, tui.Preferred)
+	if spec.Kind == PasswordField {
+		entry.SetEchoMode(tui.EchoModePassword)
+	}
+
+	return entry
+}
+
+// submitter is implemented by entries, whose submission moves the focus to
+// the next field.
+type submitter interface {
+	OnSubmit(fn func(entry *tui.Entry))
+}
+
+func (m FormField) bounds() (int64, int64) {
+	if m.Min == 0 && m.Max == 0 {
+		return math.MinInt64, math.MaxInt64
+	}
+
+	return m.Min, m.Max
+}
+
+// SetKeymap sets the keymap of the form and its inputs.
+func (m *Form) SetKeymap(keys *keymap.Keymap) {
+	m.FocusBox.SetKeymap(keys)
+
+	for _, field := range m.fields {
+		if input, ok := field.input.(interface{ SetKeymap(*keymap.Keymap) }); ok {
+			input.SetKeymap(keys)
+		}
+	}
+}
+
+// SetValidator replaces the validator of the named field, which is useful
+// when validation depends on the state of the controller.
+func (m *Form) SetValidator(name string, fn func(value interface{}) error) {
+	m.field(name).Validate = fn
+}
+
+// Input returns the input of the named field.
+func (m *Form) Input(name string) TextInput {
+	return m.field(name).input
+}
+
+func (m *Form) Text(name string) string {
+	return m.field(name).input.Text()
+}
+
+func (m *Form) SetText(name, text string) {
+	m.field(name).input.SetText(text)
+}
+
+// SetError shows the error below the named field, nil hides it.
+func (m *Form) SetError(name string, err error) {
+	m.field(name).field.SetError(err)
+}
+
+// ClearErrors hides errors of all fields.
+func (m *Form) ClearErrors() {
+	for _, field := range m.fields {
+		field.field.SetError(nil)
+	}
+}
+
+// SetFieldEnabled allows or disallows focusing the named field. Disabled
+// fields are not validated on submission.
+func (m *Form) SetFieldEnabled(name string, enabled bool) {
+	m.controller.Scope().SetWidgetEnabled(m.field(name).input, enabled)
+}
+
+// SetSubmitEnabled allows or disallows focusing the submit button.
+func (m *Form) SetSubmitEnabled(enabled bool) {
+	m.controller.Scope().SetWidgetEnabled(m.submitButton, enabled)
+}
+
+// Focus moves the focus to the named field.
+func (m *Form) Focus(name string) {
+	m.controller.Focus(m.field(name).input)
+}
+
+func (m *Form) FocusNext() {
+	m.controller.FocusNextWidget()
+}
+
+func (m *Form) FocusDefault() {
+	m.controller.FocusDefaultWidget()
+}
+
+// Reset removes the focus and errors, focusing the default field. Pending
+// validations of fields the focus has left before are dropped, so they don't
+// bring errors back.
+func (m *Form) Reset() {
+	m.controller.Focus(nil)
+	m.resetSerial = m.controller.Serial()
+	m.ClearErrors()
+	m.controller.FocusDefaultWidget()
+}
+
+// RegisterInputs marks entries of the form as text inputs in the given help
+// context, registering actions of value entries as well.
+func (m *Form) RegisterInputs(help *keymap.Context) {
+	for _, field := range m.fields {
+		if field.ReadOnly || field.Kind == EnumField || field.Kind == BoolField {
+			continue
+		}
+
+		help.RegisterInput(field.input)
+
+		switch field.Kind {
+		case AmountField, PriceField, DurationField, NumberField:
+			if field.Input == nil {
+				help.RegisterFor(field.input, keymap.Increment, "")
+				help.RegisterFor(field.input, keymap.Decrement, "")
+			}
+		}
+	}
+}
+
+// ValidateField parses and validates the named field, showing the error if
+// there is one.
+func (m *Form) ValidateField(name string) (interface{}, error) {
+	return m.validate(m.field(name))
+}
+
+// Validate validates all enabled fields, returning their values if all of
+// them are valid.
+func (m *Form) Validate() (FormValues, bool) {
+	values := FormValues{}
+	valid := true
+
+	for _, field := range m.fields {
+		if !field.ReadOnly && !m.controller.Scope().IsEnabled(field.input) {
+			continue
+		}
+
+		value, err := m.validate(field)
+		if err != nil {
+			valid = false
+			continue
+		}
+
+		values[field.Name] = value
+	}
+
+	return values, valid
+}
+
+// Submit validates the form and emits its values through OnSubmit.
+func (m *Form) Submit() {
+	if values, ok := m.Validate(); ok {
+		m.OnSubmit.Emit(values)
+	}
+}
+
+func (m *Form) validate(field *formField) (interface{}, error) {
+	value, err := m.parse(field)
+	if err == nil && field.Validate != nil && value != nil {
+		err = field.Validate(value)
+	}
+
+	field.field.SetError(err)
+	return value, err
+}
+
+func (m *Form) parse(field *formField) (interface{}, error) {
+	text := field.input.Text()
+	if field.Kind != PasswordField {
+		text = strings.TrimSpace(text)
+	}
+
+	if text == "" && field.Kind != EnumField && field.Kind != BoolField {
+		if field.Required {
+			return nil, fmt.Errorf("%s is required", strings.ToLower(field.Label))
+		}
+
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case AddressField:
+		if !common.IsHexAddress(text) {
+			return nil, fmt.Errorf("malformed address %q, expected 40 hex digits with optional 0x prefix", text)
+		}
+		return common.HexToAddress(text), nil
+	case AmountField:
+		return units.ParseAmount(text)
+	case PriceField:
+		return units.ParsePrice(text)
+	case DurationField:
+		return units.ParseDuration(text)
+	case NumberField:
+		min, max := field.bounds()
+		return parseNumber(text, min, max)
+	case EnumField:
+		for id, choice := range field.Choices {
+			if choice == text {
+				return id, nil
+			}
+		}
+		return nil, fmt.Errorf("%s is required", strings.ToLower(field.Label))
+	case BoolField:
+		return text == "true", nil
+	default:
+		return text, nil
+	}
+}
+
+// next moves the focus to the field following the given one, submitting the
+// form from the last field.
+func (m *Form) next(field *formField) {
+	var last *formField
+	for _, f := range m.fields {
+		if !f.ReadOnly && m.controller.Scope().IsEnabled(f.input) {
+			last = f
+		}
+	}
+
+	if field == last {
+		m.Submit()
+		return
+	}
+
+	m.controller.FocusNextWidget()
+}
+
+func (m *Form) highlight() {
+	for _, field := range m.fields {
+		style := "normal"
+		if field.input.IsFocused() {
+			style = "highlight"
+		}
+		field.label.SetStyleName(style)
+	}
+}
+
+func (m *Form) field(name string) *formField {
+	for _, field := range m.fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	panic("unknown form field " + name)
+}
+
+func (m *Form) fieldOf(widget tui.Widget) *formField {
+	for _, field := range m.fields {
+		if field.input == widget {
+			return field
+		}
+	}
+
+	return nil
+}
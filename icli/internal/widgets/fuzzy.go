@@ -0,0 +1,113 @@
+package widgets
+
+import (
+	"sort"
+	"unicode"
+)
+
+const (
+	fuzzyMatchScore       = 16
+	fuzzyConsecutiveBonus = 24
+	fuzzyBoundaryBonus    = 32
+	fuzzyFirstRuneBonus   = 16
+	fuzzyGapPenalty       = 1
+)
+
+// FuzzyMatch is an item matched by FuzzyFilter.
+type FuzzyMatch struct {
+	Item string
+	// Index is the position of the item in the filtered slice.
+	Index int
+	// Positions are rune indices of the item that matched the pattern.
+	Positions []int
+	Score     int
+}
+
+// FuzzyScore checks whether runes of the pattern appear in the text in the
+// same order, ignoring case, and scores the match.
+//
+// Matches of consecutive runes and matches at word boundaries, i.e. after a
+// separator or at a lower-to-upper case transition, score higher, while
+// gaps between matched runes score lower.
+func FuzzyScore(pattern, text string) (int, []int, bool) {
+	patternRunes := []rune(pattern)
+	if len(patternRunes) == 0 {
+		return 0, nil, true
+	}
+
+	textRunes := []rune(text)
+	positions := make([]int, 0, len(patternRunes))
+	score := 0
+	last := -1
+
+	for id := 0; id < len(textRunes) && len(positions) < len(patternRunes); id++ {
+		if unicode.ToLower(textRunes[id]) != unicode.ToLower(patternRunes[len(positions)]) {
+			continue
+		}
+
+		score += fuzzyMatchScore
+		switch {
+		case id == 0:
+			score += fuzzyFirstRuneBonus + fuzzyBoundaryBonus
+		case isBoundary(textRunes[id-1], textRunes[id]):
+			score += fuzzyBoundaryBonus
+		}
+
+		if last != -1 {
+			if id == last+1 {
+				score += fuzzyConsecutiveBonus
+			} else {
+				score -= (id - last - 1) * fuzzyGapPenalty
+			}
+		}
+
+		positions = append(positions, id)
+		last = id
+	}
+
+	if len(positions) != len(patternRunes) {
+		return 0, nil, false
+	}
+
+	return score, positions, true
+}
+
+func isBoundary(prev, cur rune) bool {
+	switch prev {
+	case '/', '\\', '-', '_', '.', ' ', ':':
+		return true
+	}
+
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// FuzzyFilter returns items matching the pattern, the best matches first.
+// Items with equal scores are ordered by length and then keep their
+// original order. An empty pattern matches all items in their original
+// order.
+func FuzzyFilter(pattern string, items []string) []FuzzyMatch {
+	matches := make([]FuzzyMatch, 0, len(items))
+	if pattern == "" {
+		for id, item := range items {
+			matches = append(matches, FuzzyMatch{Item: item, Index: id})
+		}
+
+		return matches
+	}
+
+	for id, item := range items {
+		if score, positions, ok := FuzzyScore(pattern, item); ok {
+			matches = append(matches, FuzzyMatch{Item: item, Index: id, Positions: positions, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+
+		return len(matches[i].Item) < len(matches[j].Item)
+	})
+
+	return matches
+}
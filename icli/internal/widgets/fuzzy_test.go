@@ -0,0 +1,111 @@
+package widgets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScorePositions(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		text      string
+		positions []int
+		ok        bool
+	}{
+		{"", "keystore", nil, true},
+		{"ks", "keystore", []int{0, 3}, true},
+		{"KS", "keystore", []int{0, 3}, true},
+		{"abc", "a-b-c", []int{0, 2, 4}, true},
+		{"ёж", "Ёлка и ёж", []int{0, 8}, true},
+		{"cba", "abc", nil, false},
+		{"abcd", "abc", nil, false},
+	}
+
+	for _, c := range cases {
+		_, positions, ok := FuzzyScore(c.pattern, c.text)
+		if ok != c.ok || !reflect.DeepEqual(positions, c.positions) {
+			t.Errorf("FuzzyScore(%q, %q) = %v, %v, expected %v, %v", c.pattern, c.text, positions, ok, c.positions, c.ok)
+		}
+	}
+}
+
+func TestFuzzyScoreOrder(t *testing.T) {
+	// Each case expects the pattern to score higher on the better text.
+	cases := []struct {
+		pattern string
+		better  string
+		worse   string
+	}{
+		{"ab", "abx", "axb"},
+		{"fb", "foo-bar", "foobar"},
+		{"fb", "fooBar", "foobar"},
+		{"fb", "foo/bar", "foobar"},
+		{"b", "bar", "abar"},
+		{"ac", "abc", "abbbc"},
+	}
+
+	for _, c := range cases {
+		better, _, ok := FuzzyScore(c.pattern, c.better)
+		if !ok {
+			t.Errorf("FuzzyScore(%q, %q) did not match", c.pattern, c.better)
+			continue
+		}
+
+		worse, _, ok := FuzzyScore(c.pattern, c.worse)
+		if !ok {
+			t.Errorf("FuzzyScore(%q, %q) did not match", c.pattern, c.worse)
+			continue
+		}
+
+		if better <= worse {
+			t.Errorf("pattern %q scored %d on %q and %d on %q, expected the former to be higher", c.pattern, better, c.better, worse, c.worse)
+		}
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	cases := []struct {
+		pattern string
+		items   []string
+		order   []int
+	}{
+		{"", []string{"b", "a", "c"}, []int{0, 1, 2}},
+		{"ab", []string{"xaxb", "abcd", "ab"}, []int{2, 1, 0}},
+		{"ab", []string{"ab-y", "ab-x"}, []int{0, 1}},
+		{"ab", []string{"ba", "xyz"}, []int{}},
+	}
+
+	for _, c := range cases {
+		matches := FuzzyFilter(c.pattern, c.items)
+
+		order := make([]int, 0, len(matches))
+		for _, match := range matches {
+			order = append(order, match.Index)
+
+			if match.Item != c.items[match.Index] {
+				t.Errorf("FuzzyFilter(%q, %q) matched %q at %d, expected %q", c.pattern, c.items, match.Item, match.Index, c.items[match.Index])
+			}
+		}
+
+		if !reflect.DeepEqual(order, c.order) {
+			t.Errorf("FuzzyFilter(%q, %q) ordered items as %v, expected %v", c.pattern, c.items, order, c.order)
+		}
+	}
+}
+
+func TestFuzzyFilterPositions(t *testing.T) {
+	matches := FuzzyFilter("sk", []string{"~/.sonm/keystore", "sk"})
+	if len(matches) != 2 {
+		t.Fatalf("FuzzyFilter matched %d items, expected 2", len(matches))
+	}
+
+	expected := map[string][]int{
+		"sk":               {0, 1},
+		"~/.sonm/keystore": {3, 8},
+	}
+	for _, match := range matches {
+		if !reflect.DeepEqual(match.Positions, expected[match.Item]) {
+			t.Errorf("positions of %q are %v, expected %v", match.Item, match.Positions, expected[match.Item])
+		}
+	}
+}
@@ -19,7 +19,8 @@ var (
 type runProgressEvent struct{}
 
 type completeProgressEvent struct {
-	Text string
+	Text  string
+	Style string
 }
 
 type AsyncLabel struct {
@@ -67,7 +68,7 @@ func (m *AsyncLabel) run(ctx context.Context, eventTxRx <-chan interface{}, rout
 				}
 
 				router.Execute(func() {
-					m.SetStyleName("ok")
+					m.SetStyleName(event.Style)
 					m.SetText(event.Text)
 				})
 			}
@@ -86,12 +87,20 @@ func (m *AsyncLabel) RunProgress(ctx context.Context) {
 }
 
 func (m *AsyncLabel) StopProgress(text string) {
-	m.eventTxRx <- &completeProgressEvent{Text: text}
+	m.eventTxRx <- &completeProgressEvent{Text: text, Style: "ok"}
 }
 
+// SetTextAsync runs the progress until the function returns the new text.
+// The text is dropped if the context is canceled by then, which allows to
+// Reset the label without being overwritten by stale results.
 func (m *AsyncLabel) SetTextAsync(ctx context.Context, fn func(ctx context.Context) string) {
 	m.RunProgress(ctx)
 	go func() {
-		m.StopProgress(fn(ctx))
+		text := fn(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.StopProgress(text)
 	}()
 }
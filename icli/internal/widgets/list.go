@@ -1,13 +1,22 @@
 package widgets
 
 import (
+	"image"
+
 	"github.com/marcusolsson/tui-go"
 )
 
+// List is a tui.List that scrolls to keep the selected item visible and
+// highlights matched runes of items, like those found by FuzzyFilter, using
+// the "list.item.match" style.
 type List struct {
 	*tui.List
 
-	items []string
+	items      []string
+	highlights map[int][]int
+	styles     map[int]string
+	offset     int
+	maxVisible int
 
 	OnKeyEventX        func(ev tui.KeyEvent) bool
 	selectedItemBefore int
@@ -21,29 +30,124 @@ func NewList() *List {
 }
 
 func (m *List) Draw(painter *tui.Painter) {
-	for idx, item := range m.items {
+	m.scrollToSelected()
+
+	for idx := m.offset; idx < len(m.items) && idx-m.offset < m.Size().Y; idx++ {
+		item := m.items[idx]
+		row := idx - m.offset
 		style := "list.item"
 
 		if idx == m.Selected() {
 			style += ".selected"
-			//item = fmt.Sprintf("❯ %s ❮", item)
-		} else {
-			//item = fmt.Sprintf("  %s  ", item)
 		}
 
 		painter.WithStyle(style, func(painter *tui.Painter) {
-			painter.FillRect(0, idx, m.Size().X, 1)
-			painter.DrawText(0, idx, item)
+			painter.FillRect(0, row, m.Size().X, 1)
+
+			if itemStyle, ok := m.styles[idx]; ok {
+				painter.WithStyle(itemStyle, func(painter *tui.Painter) {
+					m.drawItem(painter, idx, row, item)
+				})
+			} else {
+				m.drawItem(painter, idx, row, item)
+			}
 		})
 	}
 }
 
+func (m *List) drawItem(painter *tui.Painter, idx, row int, item string) {
+	positions, ok := m.highlights[idx]
+	if !ok {
+		painter.DrawText(0, row, item)
+		return
+	}
+
+	next := 0
+	for col, r := range []rune(item) {
+		if next < len(positions) && positions[next] == col {
+			next++
+			painter.WithStyle("list.item.match", func(painter *tui.Painter) {
+				painter.DrawRune(col, row, r)
+			})
+		} else {
+			painter.DrawRune(col, row, r)
+		}
+	}
+}
+
+// scrollToSelected adjusts the offset so that the selected item is visible.
+func (m *List) scrollToSelected() {
+	height := m.Size().Y
+	if height <= 0 {
+		return
+	}
+
+	selected := m.Selected()
+	switch {
+	case selected == -1:
+	case selected < m.offset:
+		m.offset = selected
+	case selected >= m.offset+height:
+		m.offset = selected - height + 1
+	}
+
+	if m.offset > len(m.items)-height {
+		m.offset = len(m.items) - height
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// SetMaxVisible limits the number of rows the list asks for, scrolling the
+// rest. Zero means no limit.
+func (m *List) SetMaxVisible(n int) {
+	m.maxVisible = n
+}
+
+func (m *List) SizeHint() image.Point {
+	hint := m.List.SizeHint()
+	if m.maxVisible > 0 && hint.Y > m.maxVisible {
+		hint.Y = m.maxVisible
+	}
+
+	return hint
+}
+
+// SetHighlights marks runes of the item at the given index to be drawn
+// highlighted. Highlights are dropped when items are replaced.
+func (m *List) SetHighlights(idx int, positions []int) {
+	if m.highlights == nil {
+		m.highlights = map[int][]int{}
+	}
+
+	m.highlights[idx] = positions
+}
+
+// ClearHighlights removes highlights of all items.
+func (m *List) ClearHighlights() {
+	m.highlights = nil
+}
+
+// SetItemStyle draws the item at the given index with the named style
+// applied on top of the list item style. Styles are dropped when items are
+// replaced.
+func (m *List) SetItemStyle(idx int, style string) {
+	if m.styles == nil {
+		m.styles = map[int]string{}
+	}
+
+	m.styles[idx] = style
+}
+
 func (m *List) AddItems(items ...string) {
 	m.items = append(m.items, items...)
 	m.List.AddItems(items...)
 }
 
 func (m *List) RemoveItem(i int) {
+	m.highlights = nil
+	m.styles = nil
 	copy(m.items[i:], m.items[i+1:])
 	m.items[len(m.items)-1] = ""
 	m.items = m.items[:len(m.items)-1]
@@ -53,6 +157,9 @@ func (m *List) RemoveItem(i int) {
 
 func (m *List) RemoveItems() {
 	m.items = nil
+	m.highlights = nil
+	m.styles = nil
+	m.offset = 0
 	m.List.RemoveItems()
 }
 
@@ -106,3 +213,27 @@ func (m *List) OnKeyEvent(ev tui.KeyEvent) {
 		m.List.OnKeyEvent(ev)
 	}
 }
+
+// CursorRow returns the row of the selected item, which makes the list
+// Scrollable.
+func (m *List) CursorRow() int {
+	return m.Selected() - m.offset
+}
+
+// MoveCursor selects the item the given number of rows away from the
+// selected one without wrapping around.
+func (m *List) MoveCursor(rows int) {
+	if m.Length() == 0 || m.Selected() < 0 {
+		return
+	}
+
+	selected := m.Selected() + rows
+	if selected < 0 {
+		selected = 0
+	}
+	if selected >= m.Length() {
+		selected = m.Length() - 1
+	}
+
+	m.Select(selected)
+}
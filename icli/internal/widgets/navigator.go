@@ -0,0 +1,202 @@
+package widgets
+
+import (
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/marcusolsson/tui-go"
+)
+
+// Pager is implemented by screens showing one of several pages at a time,
+// like Tabs, whose actions depend on the visible page.
+type Pager interface {
+	CurrentPage() tui.Widget
+}
+
+type screenEntry struct {
+	screen tui.Widget
+	hint   string
+}
+
+// Navigator is a stack of screens sharing a single status bar.
+//
+// Only the topmost screen is visible. Each screen has its own status bar hint
+// that is restored when the screen becomes visible again. Screens are
+// unfocused when they are covered by another screen and focused back when
+// revealed, so screens that track their focused widget, like FocusBox, restore
+// it automatically.
+//
+// Note that this box consumes all events bound to the "back" action, <Esc> by
+// default, when there is a screen to go back to.
+type Navigator struct {
+	*tui.Box
+
+	statusBar *tui.StatusBar
+	screens   []*screenEntry
+	contexts  map[tui.Widget]*keymap.Context
+	removals  map[tui.Widget]func()
+	keys      *keymap.Keymap
+}
+
+func NewNavigator(statusBar *tui.StatusBar) *Navigator {
+	return &Navigator{
+		Box:       tui.NewVBox(tui.NewSpacer(), statusBar),
+		statusBar: statusBar,
+		contexts:  map[tui.Widget]*keymap.Context{},
+		removals:  map[tui.Widget]func(){},
+	}
+}
+
+func (m *Navigator) SetKeymap(keys *keymap.Keymap) {
+	m.keys = keys
+}
+
+// SetContext associates actions handled by the controller of the given
+// screen with it.
+func (m *Navigator) SetContext(screen tui.Widget, context *keymap.Context) {
+	m.contexts[screen] = context
+}
+
+// RemoveContext forgets actions and the removal hook of the given screen,
+// which is meant to be done for screens that are not going to be shown
+// anymore.
+func (m *Navigator) RemoveContext(screen tui.Widget) {
+	delete(m.contexts, screen)
+	delete(m.removals, screen)
+}
+
+// OnRemoved sets the function called each time the given screen leaves the
+// stack, whether it is popped, replaced or dropped by Reset, so controllers
+// can abandon work started for it.
+func (m *Navigator) OnRemoved(screen tui.Widget, fn func()) {
+	m.removals[screen] = fn
+}
+
+// Context returns actions of the current screen or nil if there are none
+// registered. For pagers actions of the visible page take precedence.
+func (m *Navigator) Context() *keymap.Context {
+	current := m.current()
+	if current == nil {
+		return nil
+	}
+
+	if pager, ok := current.screen.(Pager); ok {
+		if context, ok := m.contexts[pager.CurrentPage()]; ok {
+			return context
+		}
+	}
+
+	return m.contexts[current.screen]
+}
+
+// Push shows the given screen on top of the current one.
+func (m *Navigator) Push(screen tui.Widget, hint string) {
+	if current := m.current(); current != nil {
+		current.screen.SetFocused(false)
+	}
+
+	m.screens = append(m.screens, &screenEntry{screen: screen, hint: hint})
+	m.show()
+}
+
+// Pop returns to the previous screen. The last screen is never popped.
+func (m *Navigator) Pop() {
+	if len(m.screens) <= 1 {
+		return
+	}
+
+	removed := m.current()
+	removed.screen.SetFocused(false)
+	m.screens = m.screens[:len(m.screens)-1]
+	m.show()
+
+	m.removed(removed)
+}
+
+// Replace substitutes the current screen with the given one.
+func (m *Navigator) Replace(screen tui.Widget, hint string) {
+	removed := m.current()
+	if removed != nil {
+		removed.screen.SetFocused(false)
+		m.screens = m.screens[:len(m.screens)-1]
+	}
+
+	m.Push(screen, hint)
+	m.removed(removed)
+}
+
+// Reset drops the whole navigation history making the given screen the only
+// one, i.e. there will be no screen to go back to.
+func (m *Navigator) Reset(screen tui.Widget, hint string) {
+	if current := m.current(); current != nil {
+		current.screen.SetFocused(false)
+	}
+
+	removed := m.screens
+	m.screens = nil
+	m.Push(screen, hint)
+
+	for id := len(removed) - 1; id >= 0; id-- {
+		m.removed(removed[id])
+	}
+}
+
+// Current returns the visible screen.
+func (m *Navigator) Current() tui.Widget {
+	if current := m.current(); current != nil {
+		return current.screen
+	}
+
+	return nil
+}
+
+// Depth returns the number of screens in the navigation stack.
+func (m *Navigator) Depth() int {
+	return len(m.screens)
+}
+
+// SetHint changes the status bar hint of the current screen.
+func (m *Navigator) SetHint(hint string) {
+	if current := m.current(); current != nil {
+		current.hint = hint
+	}
+
+	m.statusBar.SetText(hint)
+}
+
+func (m *Navigator) current() *screenEntry {
+	if len(m.screens) == 0 {
+		return nil
+	}
+
+	return m.screens[len(m.screens)-1]
+}
+
+// removed notifies that the entry has left the stack, unless the screen is
+// still shown, like when it replaces itself.
+func (m *Navigator) removed(entry *screenEntry) {
+	if entry == nil || entry.screen == m.Current() {
+		return
+	}
+
+	if fn, ok := m.removals[entry.screen]; ok {
+		fn()
+	}
+}
+
+func (m *Navigator) show() {
+	current := m.current()
+
+	m.Box.Remove(0)
+	m.Box.Insert(0, current.screen)
+	m.statusBar.SetText(current.hint)
+
+	current.screen.SetFocused(true)
+}
+
+func (m *Navigator) OnKeyEvent(ev tui.KeyEvent) {
+	if len(m.screens) > 1 && m.keys.Match(keymap.Back, ev) {
+		m.Pop()
+		return
+	}
+
+	m.Box.OnKeyEvent(ev)
+}
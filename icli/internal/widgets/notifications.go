@@ -0,0 +1,67 @@
+package widgets
+
+import (
+	"github.com/3Hren/sonmui/icli/internal/notify"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	notificationTimeFormat = "2006-01-02 15:04:05"
+)
+
+var notificationStyles = map[notify.Severity]string{
+	notify.Info:    "ok",
+	notify.Success: "success",
+	notify.Warning: "warn",
+	notify.Error:   "error",
+}
+
+// NotificationHistory is a screen listing past notifications, newest first.
+//
+// The list is scrolled using arrows, <PgUp>, <PgDn>, <Home> and <End>.
+type NotificationHistory struct {
+	*tui.Box
+
+	linesBox   *tui.Box
+	scrollView *ScrollView
+	limit      int
+}
+
+func NewNotificationHistory(history []notify.Notification) *NotificationHistory {
+	linesBox := tui.NewVBox()
+	scrollView := NewScrollView(tui.NewVBox(linesBox, tui.NewSpacer()))
+
+	box := tui.NewVBox(scrollView)
+	box.SetBorder(true)
+	box.SetTitle("Notifications")
+
+	m := &NotificationHistory{
+		Box:        box,
+		linesBox:   linesBox,
+		scrollView: scrollView,
+		limit:      notify.DefaultHistorySize,
+	}
+
+	for _, notification := range history {
+		m.Add(notification)
+	}
+
+	return m
+}
+
+// Add puts the given notification on top of the list. Must be called from the
+// UI thread.
+func (m *NotificationHistory) Add(notification notify.Notification) {
+	line := tui.NewLabel(notification.Time.Format(notificationTimeFormat) + "  " + notification.Message)
+	line.SetStyleName(notificationStyles[notification.Severity])
+
+	m.linesBox.Insert(0, line)
+	if m.linesBox.Length() > m.limit {
+		m.linesBox.Remove(m.linesBox.Length() - 1)
+	}
+}
+
+func (m *NotificationHistory) SetFocused(v bool) {
+	m.Box.SetFocused(v)
+	m.scrollView.SetFocused(v)
+}
@@ -0,0 +1,28 @@
+package widgets
+
+import (
+	"github.com/marcusolsson/tui-go"
+)
+
+// KeyObserver wraps the given widget, calling the function for every key
+// event before passing it further.
+//
+// Only key events can be observed. tui-go v0.4.0 neither enables mouse
+// reporting of the terminal nor passes mouse events to widgets, and its
+// tui.MouseEvent carries neither buttons nor the wheel direction, so
+// clicking and scrolling can't be supported until the library is replaced
+// or upgraded.
+type KeyObserver struct {
+	tui.Widget
+
+	fn func(ev tui.KeyEvent)
+}
+
+func NewKeyObserver(widget tui.Widget, fn func(ev tui.KeyEvent)) *KeyObserver {
+	return &KeyObserver{Widget: widget, fn: fn}
+}
+
+func (m *KeyObserver) OnKeyEvent(ev tui.KeyEvent) {
+	m.fn(ev)
+	m.Widget.OnKeyEvent(ev)
+}
@@ -0,0 +1,235 @@
+package widgets
+
+import (
+	"image"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Scrollable is implemented by widgets having a cursor, like lists, so the
+// ScrollView can keep the cursor visible and move it page by page.
+type Scrollable interface {
+	tui.Widget
+	// CursorRow returns the row of the cursor relative to the widget's top
+	// or -1 if there is no cursor, for example when unfocused.
+	CursorRow() int
+	// MoveCursor moves the cursor by the given number of rows, stopping at
+	// the first and the last one.
+	MoveCursor(rows int)
+}
+
+// ScrollView wraps a widget that may be taller than the space available,
+// scrolling it vertically and drawing a scrollbar in the rightmost column
+// using "scrollbar" and "scrollbar.thumb" styles.
+//
+// If the widget is Scrollable, the view follows its cursor and <PgUp>,
+// <PgDn>, <Home> and <End> move the cursor while it is shown. Otherwise these
+// keys, as well as <Up> and <Down>, scroll the view itself while it is
+// focused.
+type ScrollView struct {
+	tui.WidgetBase
+
+	widget     tui.Widget
+	offset     int
+	cursor     int
+	maxVisible int
+}
+
+func NewScrollView(widget tui.Widget) *ScrollView {
+	return &ScrollView{
+		widget: widget,
+		cursor: -1,
+	}
+}
+
+// SetFocused passes the focus to the wrapped widget as well, so the view can
+// be put into focus chains instead of the widget.
+func (m *ScrollView) SetFocused(v bool) {
+	m.WidgetBase.SetFocused(v)
+	m.widget.SetFocused(v)
+}
+
+// SetMaxVisible limits the number of rows the view asks for, scrolling the
+// rest. Zero means no limit.
+func (m *ScrollView) SetMaxVisible(n int) {
+	m.maxVisible = n
+}
+
+func (m *ScrollView) SizeHint() image.Point {
+	hint := m.widget.SizeHint()
+	if m.maxVisible > 0 && hint.Y > m.maxVisible {
+		hint.X++
+		hint.Y = m.maxVisible
+	}
+
+	return hint
+}
+
+func (m *ScrollView) MinSizeHint() image.Point {
+	return image.Pt(m.widget.MinSizeHint().X, 1)
+}
+
+func (m *ScrollView) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	return m.widget.SizePolicy()
+}
+
+func (m *ScrollView) Resize(size image.Point) {
+	m.WidgetBase.Resize(size)
+
+	hint := m.widget.SizeHint()
+	width := size.X
+	if hint.Y > size.Y {
+		width--
+	}
+
+	m.widget.Resize(image.Pt(width, max(hint.Y, size.Y)))
+	m.clamp()
+}
+
+func (m *ScrollView) Draw(painter *tui.Painter) {
+	m.followCursor()
+
+	size := m.Size()
+
+	painter.Translate(0, -m.offset)
+	painter.WithMask(image.Rect(0, m.offset, size.X, m.offset+size.Y), func(painter *tui.Painter) {
+		m.widget.Draw(painter)
+	})
+	painter.Restore()
+
+	if m.overflows() {
+		m.drawScrollbar(painter)
+	}
+}
+
+func (m *ScrollView) drawScrollbar(painter *tui.Painter) {
+	size := m.Size()
+	total := m.widget.Size().Y
+
+	thumbSize := max(1, size.Y*size.Y/total)
+	thumbPos := 0
+	if total > size.Y {
+		thumbPos = (size.Y - thumbSize) * m.offset / (total - size.Y)
+	}
+
+	x := size.X - 1
+	painter.WithStyle("scrollbar", func(painter *tui.Painter) {
+		for y := 0; y < size.Y; y++ {
+			if y >= thumbPos && y < thumbPos+thumbSize {
+				painter.WithStyle("scrollbar.thumb", func(painter *tui.Painter) {
+					painter.DrawRune(x, y, '█')
+				})
+			} else {
+				painter.DrawRune(x, y, '│')
+			}
+		}
+	})
+}
+
+func (m *ScrollView) OnKeyEvent(ev tui.KeyEvent) {
+	m.widget.OnKeyEvent(ev)
+
+	if scrollable, ok := m.widget.(Scrollable); ok {
+		if scrollable.CursorRow() < 0 {
+			return
+		}
+
+		switch ev.Key {
+		case tui.KeyPgUp:
+			scrollable.MoveCursor(-m.page())
+		case tui.KeyPgDn:
+			scrollable.MoveCursor(m.page())
+		case tui.KeyHome:
+			scrollable.MoveCursor(-scrollable.Size().Y)
+		case tui.KeyEnd:
+			scrollable.MoveCursor(scrollable.Size().Y)
+		}
+		return
+	}
+
+	if !m.IsFocused() {
+		return
+	}
+
+	switch ev.Key {
+	case tui.KeyUp:
+		m.ScrollBy(-1)
+	case tui.KeyDown:
+		m.ScrollBy(1)
+	case tui.KeyPgUp:
+		m.ScrollBy(-m.page())
+	case tui.KeyPgDn:
+		m.ScrollBy(m.page())
+	case tui.KeyHome:
+		m.ScrollToTop()
+	case tui.KeyEnd:
+		m.ScrollToBottom()
+	}
+}
+
+// ScrollBy scrolls the view by the given number of rows.
+func (m *ScrollView) ScrollBy(rows int) {
+	m.offset += rows
+	m.clamp()
+}
+
+// ScrollToTop scrolls to the first row.
+func (m *ScrollView) ScrollToTop() {
+	m.offset = 0
+}
+
+// ScrollToBottom scrolls to the last row.
+func (m *ScrollView) ScrollToBottom() {
+	m.offset = m.widget.Size().Y
+	m.clamp()
+}
+
+// followCursor scrolls to the cursor of the Scrollable widget when it has
+// moved, so the user is still able to scroll away from it otherwise.
+func (m *ScrollView) followCursor() {
+	scrollable, ok := m.widget.(Scrollable)
+	if !ok {
+		return
+	}
+
+	cursor := scrollable.CursorRow()
+	if cursor == m.cursor {
+		return
+	}
+	m.cursor = cursor
+
+	switch {
+	case cursor < 0:
+	case cursor < m.offset:
+		m.offset = cursor
+	case cursor >= m.offset+m.Size().Y:
+		m.offset = cursor - m.Size().Y + 1
+	}
+	m.clamp()
+}
+
+// page returns the number of rows to scroll by a single page, keeping the
+// last row of the previous page visible.
+func (m *ScrollView) page() int {
+	return max(1, m.Size().Y-1)
+}
+
+func (m *ScrollView) overflows() bool {
+	return m.widget.Size().Y > m.Size().Y
+}
+
+func (m *ScrollView) clamp() {
+	if limit := m.widget.Size().Y - m.Size().Y; m.offset > limit {
+		m.offset = limit
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,204 @@
+package widgets
+
+import (
+	"image"
+	"unicode/utf8"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+type tabPage struct {
+	title  string
+	widget tui.Widget
+}
+
+// Tabs shows one of several pages at a time below a bar with their titles.
+//
+// The style of the bar is taken from the theme, i.e. "tab" for inactive tabs
+// and "tab.selected" for the current one. Key events are passed to the
+// current page only.
+type Tabs struct {
+	tui.WidgetBase
+
+	pages   []*tabPage
+	current int
+}
+
+func NewTabs() *Tabs {
+	return &Tabs{current: -1}
+}
+
+// Append adds a page to the end of the bar. The first page added becomes the
+// current one.
+func (m *Tabs) Append(title string, page tui.Widget) {
+	m.pages = append(m.pages, &tabPage{title: title, widget: page})
+	m.resizePage(page)
+
+	if m.current == -1 {
+		m.SetCurrent(page)
+	}
+}
+
+// Remove drops the given page, making the previous one current if the page
+// was current.
+func (m *Tabs) Remove(page tui.Widget) {
+	pos := m.pos(page)
+	if pos == -1 {
+		return
+	}
+
+	if pos == m.current {
+		page.SetFocused(false)
+	}
+
+	m.pages = append(m.pages[:pos], m.pages[pos+1:]...)
+
+	switch {
+	case len(m.pages) == 0:
+		m.current = -1
+	case pos < m.current:
+		m.current--
+	case pos == m.current:
+		if m.current > 0 {
+			m.current--
+		}
+		m.show()
+	}
+}
+
+// SetTitle changes the title of the given page.
+func (m *Tabs) SetTitle(page tui.Widget, title string) {
+	if pos := m.pos(page); pos != -1 {
+		m.pages[pos].title = title
+	}
+}
+
+// SetCurrent makes the given page visible.
+func (m *Tabs) SetCurrent(page tui.Widget) {
+	pos := m.pos(page)
+	if pos == -1 || pos == m.current {
+		return
+	}
+
+	if current := m.CurrentPage(); current != nil {
+		current.SetFocused(false)
+	}
+
+	m.current = pos
+	m.show()
+}
+
+// Next makes the next page visible, wrapping around after the last one.
+func (m *Tabs) Next() {
+	if len(m.pages) > 1 {
+		m.SetCurrent(m.pages[(m.current+1)%len(m.pages)].widget)
+	}
+}
+
+// CurrentPage returns the visible page or nil if there are no pages.
+func (m *Tabs) CurrentPage() tui.Widget {
+	if m.current == -1 {
+		return nil
+	}
+
+	return m.pages[m.current].widget
+}
+
+func (m *Tabs) Length() int {
+	return len(m.pages)
+}
+
+func (m *Tabs) pos(page tui.Widget) int {
+	for id, p := range m.pages {
+		if p.widget == page {
+			return id
+		}
+	}
+
+	return -1
+}
+
+func (m *Tabs) show() {
+	page := m.CurrentPage()
+	m.resizePage(page)
+
+	if m.IsFocused() {
+		page.SetFocused(true)
+	}
+}
+
+func (m *Tabs) resizePage(page tui.Widget) {
+	size := m.Size()
+	if size.Y > 0 {
+		size.Y--
+	}
+
+	page.Resize(size)
+}
+
+func (m *Tabs) Draw(painter *tui.Painter) {
+	x := 0
+	for id, page := range m.pages {
+		style := "tab"
+		if id == m.current {
+			style = "tab.selected"
+		}
+
+		text := " " + page.title + " "
+		painter.WithStyle(style, func(painter *tui.Painter) {
+			painter.DrawText(x, 0, text)
+		})
+		x += utf8.RuneCountInString(text)
+
+		painter.DrawRune(x, 0, '│')
+		x++
+	}
+
+	if page := m.CurrentPage(); page != nil {
+		painter.Translate(0, 1)
+		page.Draw(painter)
+		painter.Restore()
+	}
+}
+
+func (m *Tabs) Resize(size image.Point) {
+	m.WidgetBase.Resize(size)
+
+	for _, page := range m.pages {
+		m.resizePage(page.widget)
+	}
+}
+
+func (m *Tabs) MinSizeHint() image.Point {
+	if page := m.CurrentPage(); page != nil {
+		return page.MinSizeHint().Add(image.Point{Y: 1})
+	}
+
+	return image.Point{Y: 1}
+}
+
+func (m *Tabs) SizeHint() image.Point {
+	if page := m.CurrentPage(); page != nil {
+		return page.SizeHint().Add(image.Point{Y: 1})
+	}
+
+	return image.Point{Y: 1}
+}
+
+func (m *Tabs) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	return tui.Expanding, tui.Expanding
+}
+
+func (m *Tabs) SetFocused(focused bool) {
+	m.WidgetBase.SetFocused(focused)
+
+	if page := m.CurrentPage(); page != nil {
+		page.SetFocused(focused)
+	}
+}
+
+func (m *Tabs) OnKeyEvent(ev tui.KeyEvent) {
+	if page := m.CurrentPage(); page != nil {
+		page.OnKeyEvent(ev)
+	}
+}
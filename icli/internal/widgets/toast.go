@@ -0,0 +1,128 @@
+package widgets
+
+import (
+	"image"
+	"time"
+
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/notify"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	defaultToastDuration      = 4 * time.Second
+	defaultErrorToastDuration = 8 * time.Second
+	maxQueuedToasts           = 8
+)
+
+// Toaster draws transient notifications over the bottom line of the wrapped
+// widget, which is expected to be the status bar.
+//
+// Toasts are shown one at a time in the order they were posted, each for a
+// few seconds depending on its severity. The style of a toast is taken from
+// the theme, i.e. "toast.info", "toast.success", "toast.warn" or
+// "toast.error".
+type Toaster struct {
+	tui.WidgetBase
+
+	root   tui.Widget
+	router *mp.Router
+
+	current *notify.Notification
+	queue   []notify.Notification
+	timer   *time.Timer
+}
+
+func NewToaster(root tui.Widget, router *mp.Router) *Toaster {
+	return &Toaster{root: root, router: router}
+}
+
+// Show enqueues the given notification. Must be called from the UI thread.
+func (m *Toaster) Show(notification notify.Notification) {
+	if m.current == nil {
+		m.show(notification)
+		return
+	}
+
+	m.queue = append(m.queue, notification)
+	if len(m.queue) > maxQueuedToasts {
+		m.queue = m.queue[len(m.queue)-maxQueuedToasts:]
+	}
+}
+
+// Dismiss hides the current toast, showing the next one if any.
+func (m *Toaster) Dismiss() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+
+	m.current = nil
+	if len(m.queue) > 0 {
+		next := m.queue[0]
+		m.queue = m.queue[1:]
+		m.show(next)
+	}
+}
+
+func (m *Toaster) show(notification notify.Notification) {
+	m.current = &notification
+
+	duration := defaultToastDuration
+	if notification.Severity == notify.Error {
+		duration = defaultErrorToastDuration
+	}
+
+	current := m.current
+	m.timer = time.AfterFunc(duration, func() {
+		m.router.Execute(func() {
+			// The toast may already be dismissed manually.
+			if m.current == current {
+				m.Dismiss()
+			}
+		})
+	})
+}
+
+func (m *Toaster) Draw(painter *tui.Painter) {
+	m.root.Draw(painter)
+
+	if m.current == nil {
+		return
+	}
+
+	size := m.Size()
+	painter.WithStyle("toast."+m.current.Severity.String(), func(painter *tui.Painter) {
+		painter.FillRect(0, size.Y-1, size.X, 1)
+		painter.DrawText(1, size.Y-1, m.current.Message)
+	})
+}
+
+func (m *Toaster) Resize(size image.Point) {
+	m.WidgetBase.Resize(size)
+	m.root.Resize(size)
+}
+
+func (m *Toaster) MinSizeHint() image.Point {
+	return m.root.MinSizeHint()
+}
+
+func (m *Toaster) SizeHint() image.Point {
+	return m.root.SizeHint()
+}
+
+func (m *Toaster) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	return m.root.SizePolicy()
+}
+
+func (m *Toaster) IsFocused() bool {
+	return m.root.IsFocused()
+}
+
+func (m *Toaster) SetFocused(focused bool) {
+	m.root.SetFocused(focused)
+}
+
+func (m *Toaster) OnKeyEvent(ev tui.KeyEvent) {
+	m.root.OnKeyEvent(ev)
+}
@@ -0,0 +1,326 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/3Hren/sonmui/icli/internal/units"
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	// valueEntryWidth is the preferred width of value entries, not counting
+	// the preview.
+	valueEntryWidth = 20
+)
+
+// ValueEntry is an entry for typed values, like amounts or durations, shown
+// with a formatted preview of the parsed value to its right.
+//
+// Runes that can't be part of a value are ignored. Actions "increment" and
+// "decrement", <Up> and <Down> by default, step the last number of the text
+// by its least significant digit, so "1.5 SNM" becomes "1.6 SNM" and
+// "1h30m" becomes "1h31m".
+//
+// Use one of AmountEntry, PriceEntry, DurationEntry or NumberEntry.
+type ValueEntry struct {
+	*tui.Box
+
+	entry   *Entry
+	preview *tui.Label
+	keys    *keymap.Keymap
+
+	accept func(r rune) bool
+	parse  func(text string) (interface{}, error)
+	format func(value interface{}) string
+	step   func(text string, delta int) string
+}
+
+func newValueEntry(accept func(r rune) bool, parse func(text string) (interface{}, error), format func(value interface{}) string) *ValueEntry {
+	entry := NewEntry()
+	entry.SetSizeHint(image.Pt(valueEntryWidth, 1))
+	entry.SetSizePolicy(tui.Expanding, tui.Preferred)
+
+	preview := tui.NewLabel("")
+	preview.SetStyleName("normal")
+
+	return &ValueEntry{
+		Box:     tui.NewHBox(entry, tui.NewPadder(1, 0, preview)),
+		entry:   entry,
+		preview: preview,
+		accept:  accept,
+		parse:   parse,
+		format:  format,
+		step:    stepLastNumber,
+	}
+}
+
+func (m *ValueEntry) SetKeymap(keys *keymap.Keymap) {
+	m.keys = keys
+}
+
+func (m *ValueEntry) SetFocused(focused bool) {
+	m.entry.SetFocused(focused)
+}
+
+func (m *ValueEntry) IsFocused() bool {
+	return m.entry.IsFocused()
+}
+
+func (m *ValueEntry) Text() string {
+	return m.entry.Text()
+}
+
+func (m *ValueEntry) SetText(text string) {
+	m.entry.SetText(text)
+	m.updatePreview()
+}
+
+func (m *ValueEntry) OnSubmit(fn func(entry *tui.Entry)) {
+	m.entry.OnSubmit(fn)
+}
+
+// Value parses the text of the entry.
+func (m *ValueEntry) Value() (interface{}, error) {
+	return m.parse(strings.TrimSpace(m.entry.Text()))
+}
+
+func (m *ValueEntry) OnKeyEvent(ev tui.KeyEvent) {
+	if !m.IsFocused() {
+		return
+	}
+
+	switch {
+	case m.keys.Match(keymap.Increment, ev):
+		m.SetText(m.step(m.entry.Text(), 1))
+	case m.keys.Match(keymap.Decrement, ev):
+		m.SetText(m.step(m.entry.Text(), -1))
+	case ev.Key == tui.KeyRune && ev.Modifiers == tui.ModNone && !m.accept(ev.Rune):
+	default:
+		m.entry.OnKeyEvent(ev)
+		m.updatePreview()
+	}
+}
+
+func (m *ValueEntry) updatePreview() {
+	value, err := m.Value()
+	if err != nil || m.format == nil {
+		m.preview.SetText("")
+		return
+	}
+
+	m.preview.SetText("= " + m.format(value))
+}
+
+// AmountEntry is an entry for token amounts, like "1.5", "1.5 SNM" or
+// "100 wei", with values in wei.
+type AmountEntry struct {
+	*ValueEntry
+}
+
+func NewAmountEntry() *AmountEntry {
+	return &AmountEntry{
+		ValueEntry: newValueEntry(acceptUnits("SNMwei"),
+			func(text string) (interface{}, error) { return units.ParseAmount(text) },
+			func(value interface{}) string { return units.FormatAmount(value.(*big.Int)) },
+		),
+	}
+}
+
+// Amount returns the amount in wei.
+func (m *AmountEntry) Amount() (*big.Int, error) {
+	value, err := m.Value()
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*big.Int), nil
+}
+
+func (m *AmountEntry) SetAmount(amount *big.Int) {
+	m.SetText(strings.TrimSuffix(units.FormatAmount(amount), " SNM"))
+}
+
+// PriceEntry is an entry for prices, like "0.5 USD/h" or "0.0001 USD/s",
+// with values in USD per second scaled by 10^18.
+type PriceEntry struct {
+	*ValueEntry
+}
+
+func NewPriceEntry() *PriceEntry {
+	return &PriceEntry{
+		ValueEntry: newValueEntry(acceptUnits("USDhs/"),
+			func(text string) (interface{}, error) { return units.ParsePrice(text) },
+			func(value interface{}) string { return units.FormatPrice(value.(*big.Int)) },
+		),
+	}
+}
+
+// Price returns the price in USD per second scaled by 10^18.
+func (m *PriceEntry) Price() (*big.Int, error) {
+	value, err := m.Value()
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*big.Int), nil
+}
+
+func (m *PriceEntry) SetPrice(price *big.Int) {
+	m.SetText(units.FormatPrice(price))
+}
+
+// DurationEntry is an entry for durations, like "1h30m" or "2d".
+type DurationEntry struct {
+	*ValueEntry
+}
+
+func NewDurationEntry() *DurationEntry {
+	return &DurationEntry{
+		ValueEntry: newValueEntry(acceptUnits("dhmsuµn"),
+			func(text string) (interface{}, error) { return units.ParseDuration(text) },
+			func(value interface{}) string { return units.FormatDuration(value.(time.Duration)) },
+		),
+	}
+}
+
+func (m *DurationEntry) Duration() (time.Duration, error) {
+	value, err := m.Value()
+	if err != nil {
+		return 0, err
+	}
+
+	return value.(time.Duration), nil
+}
+
+func (m *DurationEntry) SetDuration(duration time.Duration) {
+	m.SetText(units.FormatDuration(duration))
+}
+
+// NumberEntry is an entry for integers within the given range, like the
+// number of CPU cores. Stepping stops at the bounds and starts from 0 if the
+// entry is empty.
+type NumberEntry struct {
+	*ValueEntry
+}
+
+func NewNumberEntry(min, max int64) *NumberEntry {
+	accept := func(r rune) bool {
+		return unicode.IsDigit(r) || r == '-' && min < 0
+	}
+
+	m := &NumberEntry{
+		ValueEntry: newValueEntry(accept, func(text string) (interface{}, error) { return parseNumber(text, min, max) }, nil),
+	}
+	// An empty or malformed text steps from 0, so unbounded entries don't
+	// jump to the lowest integer.
+	m.step = func(text string, delta int) string {
+		value, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			value = 0
+		}
+		if delta > 0 && value < max || delta < 0 && value > min {
+			value += int64(delta)
+		}
+
+		return strconv.FormatInt(clamp(value, min, max), 10)
+	}
+
+	return m
+}
+
+func (m *NumberEntry) Number() (int64, error) {
+	value, err := m.Value()
+	if err != nil {
+		return 0, err
+	}
+
+	return value.(int64), nil
+}
+
+func (m *NumberEntry) SetNumber(value int64) {
+	m.SetText(strconv.FormatInt(value, 10))
+}
+
+func parseNumber(text string, min, max int64) (int64, error) {
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed number %q, expected an integer", text)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("%d is out of range, expected a number from %d to %d", value, min, max)
+	}
+
+	return value, nil
+}
+
+func clamp(value, min, max int64) int64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+
+	return value
+}
+
+// acceptUnits accepts digits, the decimal point, spaces and letters of the
+// given units regardless of their case.
+func acceptUnits(letters string) func(r rune) bool {
+	return func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.' || r == ' ' ||
+			strings.ContainsRune(strings.ToLower(letters), unicode.ToLower(r))
+	}
+}
+
+// stepLastNumber adds the delta to the last decimal number found in the
+// text, scaled by its least significant digit, keeping the number of
+// decimal places. Numbers never get negative. An empty text steps from 0.
+func stepLastNumber(text string, delta int) string {
+	end := strings.LastIndexFunc(text, isDecimal) + 1
+	if end == 0 {
+		if strings.TrimSpace(text) == "" && delta > 0 {
+			return strconv.Itoa(delta)
+		}
+		return text
+	}
+
+	begin := strings.LastIndexFunc(text[:end], func(r rune) bool { return !isDecimal(r) }) + 1
+	number := text[begin:end]
+
+	whole, frac := number, ""
+	if id := strings.IndexByte(number, '.'); id >= 0 {
+		whole, frac = number[:id], number[id+1:]
+	}
+
+	value, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		return text
+	}
+
+	value.Add(value, big.NewInt(int64(delta)))
+	if value.Sign() < 0 {
+		value.SetInt64(0)
+	}
+
+	digits := value.String()
+	if len(digits) <= len(frac) {
+		digits = strings.Repeat("0", len(frac)-len(digits)+1) + digits
+	}
+	if len(frac) > 0 {
+		digits = digits[:len(digits)-len(frac)] + "." + digits[len(digits)-len(frac):]
+	}
+
+	return text[:begin] + digits + text[end:]
+}
+
+func isDecimal(r rune) bool {
+	return r >= '0' && r <= '9' || r == '.'
+}
@@ -0,0 +1,34 @@
+package widgets
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumberEntryStep(t *testing.T) {
+	cases := []struct {
+		min, max int64
+		text     string
+		delta    int
+		expected string
+	}{
+		{math.MinInt64, math.MaxInt64, "", 1, "1"},
+		{math.MinInt64, math.MaxInt64, "", -1, "-1"},
+		{math.MinInt64, math.MaxInt64, " ", 1, "1"},
+		{1, 64, "", 1, "1"},
+		{1, 64, "", -1, "1"},
+		{-10, -5, "", 1, "-5"},
+		{0, 10, "5", 1, "6"},
+		{0, 10, "10", 1, "10"},
+		{0, 10, "0", -1, "0"},
+		{0, 10, "42", -1, "10"},
+		{math.MinInt64, math.MaxInt64, "9223372036854775807", 1, "9223372036854775807"},
+	}
+
+	for _, c := range cases {
+		m := NewNumberEntry(c.min, c.max)
+		if actual := m.step(c.text, c.delta); actual != c.expected {
+			t.Errorf("step(%q, %d) in [%d, %d] = %q, expected %q", c.text, c.delta, c.min, c.max, actual, c.expected)
+		}
+	}
+}
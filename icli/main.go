@@ -3,26 +3,36 @@ package main
 import (
 	"context"
 	"crypto/ecdsa"
+	"flag"
 	"fmt"
-	"image"
+	"math/big"
 	"os"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/3Hren/sonmui/icli/internal/addressbook"
 	"github.com/3Hren/sonmui/icli/internal/config"
 	"github.com/3Hren/sonmui/icli/internal/interactions"
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/3Hren/sonmui/icli/internal/logging"
+	"github.com/3Hren/sonmui/icli/internal/metrics"
 	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/notify"
+	"github.com/3Hren/sonmui/icli/internal/rpc"
+	"github.com/3Hren/sonmui/icli/internal/session"
+	"github.com/3Hren/sonmui/icli/internal/signer"
+	"github.com/3Hren/sonmui/icli/internal/themes"
+	"github.com/3Hren/sonmui/icli/internal/units"
 	"github.com/3Hren/sonmui/icli/internal/views"
 	"github.com/3Hren/sonmui/icli/internal/widgets"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/marcusolsson/tui-go"
-	"github.com/sonm-io/core/accounts"
-	"github.com/sonm-io/core/insonmnia/auth"
 	"github.com/sonm-io/core/insonmnia/version"
-	"github.com/sonm-io/core/proto"
 	"github.com/sonm-io/core/util"
-	"github.com/sonm-io/core/util/xgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 type MainView struct {
@@ -34,6 +44,11 @@ type MainView struct {
 	currentBalanceVLabel *widgets.AsyncLabel
 	orderCountVLabel     *widgets.AsyncLabel
 	dealCountVLabel      *widgets.AsyncLabel
+	tasksCPUVLabel       *tui.Label
+	tasksCPULine         *widgets.Sparkline
+	tasksMemoryVLabel    *tui.Label
+	tasksMemoryLine      *widgets.Sparkline
+	balancePlot          *widgets.LinePlot
 	menuList             *widgets.List
 	submenuList          *widgets.List
 
@@ -41,9 +56,11 @@ type MainView struct {
 	menuBox     *tui.Box
 	submenuBox  *tui.Box
 	workersView *WorkerListWidget
+	// workersPanel is the worker list anchored for spatial navigation.
+	workersPanel tui.Widget
 }
 
-func NewMainView(ctx context.Context, router *mp.Router) *MainView {
+func NewMainView(ctx context.Context, book *addressbook.Book, router *mp.Router) *MainView {
 	currentNodeNLabel := tui.NewLabel("Node:")
 	currentNodeNLabel.SetStyleName("bold")
 	currentNodeVLabel := widgets.NewAsyncLabel(ctx, "-", router)
@@ -59,6 +76,14 @@ func NewMainView(ctx context.Context, router *mp.Router) *MainView {
 	dealCountNLabel := tui.NewLabel("Deals:")
 	dealCountNLabel.SetStyleName("bold")
 	dealCountVLabel := widgets.NewAsyncLabel(ctx, "-", router)
+	tasksCPUNLabel := tui.NewLabel("Tasks CPU:")
+	tasksCPUNLabel.SetStyleName("bold")
+	tasksCPUVLabel := tui.NewLabel("-")
+	tasksCPULine := widgets.NewSparkline()
+	tasksMemoryNLabel := tui.NewLabel("Tasks RAM:")
+	tasksMemoryNLabel.SetStyleName("bold")
+	tasksMemoryVLabel := tui.NewLabel("-")
+	tasksMemoryLine := widgets.NewSparkline()
 
 	nColumnBox := tui.NewVBox(
 		currentNodeNLabel,
@@ -66,6 +91,8 @@ func NewMainView(ctx context.Context, router *mp.Router) *MainView {
 		currentBalanceNLabel,
 		orderCountNLabel,
 		dealCountNLabel,
+		tasksCPUNLabel,
+		tasksMemoryNLabel,
 	)
 	vColumnBox := tui.NewVBox(
 		currentNodeVLabel,
@@ -73,27 +100,34 @@ func NewMainView(ctx context.Context, router *mp.Router) *MainView {
 		currentBalanceVLabel,
 		orderCountVLabel,
 		dealCountVLabel,
+		tui.NewHBox(tasksCPUVLabel, tui.NewPadder(1, 0, tasksCPULine), tui.NewSpacer()),
+		tui.NewHBox(tasksMemoryVLabel, tui.NewPadder(1, 0, tasksMemoryLine), tui.NewSpacer()),
 	)
 	vColumnBox.SetSizePolicy(tui.Expanding, tui.Preferred)
 
-	summaryBox := tui.NewHBox(tui.NewPadder(1, 0, nColumnBox), vColumnBox)
+	balancePlot := widgets.NewLinePlot(vColumnBox.Length())
+	balancePlot.SetFormatter(func(value float64) string {
+		return fmt.Sprintf("%.4g", value)
+	})
+
+	summaryBox := tui.NewVBox(widgets.NewScrollView(tui.NewHBox(tui.NewPadder(1, 0, nColumnBox), vColumnBox, tui.NewPadder(1, 0, balancePlot))))
 	summaryBox.SetBorder(true)
 	summaryBox.SetSizePolicy(tui.Preferred, tui.Minimum)
 
 	menuList := widgets.NewList()
-	menuList.AddItems("Workers", "Exit")
+	menuList.AddItems("Accounts", "Market", "Workers", "Exit")
 
-	menuBox := tui.NewVBox(tui.NewPadder(1, 0, menuList), tui.NewPadder(32, 0, tui.NewSpacer()))
+	menuBox := tui.NewVBox(tui.NewPadder(1, 0, interactions.NewAnchor(menuList)), tui.NewPadder(32, 0, tui.NewSpacer()))
 	menuBox.SetBorder(true)
 	menuBox.SetSizePolicy(tui.Preferred, tui.Preferred)
 
 	submenuList := widgets.NewList()
 
-	submenuBox := tui.NewHBox(tui.NewPadder(1, 0, submenuList))
+	submenuBox := tui.NewHBox(tui.NewPadder(1, 0, interactions.NewAnchor(submenuList)))
 	submenuBox.SetBorder(true)
 	submenuBox.SetSizePolicy(tui.Expanding, tui.Preferred)
 
-	workersView := NewWorkerListWidget(router)
+	workersView := NewWorkerListWidget(book, router)
 	workersView.SetBorder(true)
 	workersView.SetSizePolicy(tui.Expanding, tui.Preferred)
 
@@ -114,22 +148,29 @@ func NewMainView(ctx context.Context, router *mp.Router) *MainView {
 		currentBalanceVLabel: currentBalanceVLabel,
 		orderCountVLabel:     orderCountVLabel,
 		dealCountVLabel:      dealCountVLabel,
+		tasksCPUVLabel:       tasksCPUVLabel,
+		tasksCPULine:         tasksCPULine,
+		tasksMemoryVLabel:    tasksMemoryVLabel,
+		tasksMemoryLine:      tasksMemoryLine,
+		balancePlot:          balancePlot,
 		menuList:             menuList,
 		submenuList:          submenuList,
 
-		controlBox:  controlBox,
-		menuBox:     menuBox,
-		submenuBox:  submenuBox,
-		workersView: workersView,
+		controlBox:   controlBox,
+		menuBox:      menuBox,
+		submenuBox:   submenuBox,
+		workersView:  workersView,
+		workersPanel: interactions.NewAnchor(workersView),
 	}
 }
 
 type nodeConnectEvent struct {
-	Addr       string
-	PrivateKey *ecdsa.PrivateKey
+	Addr   string
+	Signer signer.Signer
 }
 
 type nodeConnectionResultEvent struct {
+	ID    int
 	Addr  string
 	Conn  *grpc.ClientConn
 	Error error
@@ -142,12 +183,27 @@ type workerConfirmEvent struct {
 }
 
 type workerConfirmDoneEvent struct {
-	ID    string
-	Error error
+	ConnID int
+	ID     string
+	Error  error
 }
 
 type workersUpdateUptimeEvent struct{}
 
+type transferEvent struct {
+	To     common.Address
+	Amount *big.Int
+}
+
+type bidOrderEvent struct {
+	Price      *big.Int
+	Duration   time.Duration
+	Benchmarks map[string]uint64
+}
+
+// ordersUpdateEvent reloads the number of orders.
+type ordersUpdateEvent struct{}
+
 type confirmationStatus int
 
 const (
@@ -163,28 +219,35 @@ type workerItem struct {
 
 // ========================================================================================================================
 
-// WorkerListWidget is an interactive worker list.
+// WorkerListWidget is an interactive worker list. Workers known by the
+// address book are shown by their names.
 //
 // | 0x... | [v] |
+//
+// Below the list there are charts of workers by confirmation status and of
+// the number of workers over time.
 type WorkerListWidget struct {
 	*tui.Box
 
 	router *mp.Router
+	book   *addressbook.Book
 
 	workers          []*workerItem
 	workersList      *widgets.List
 	workersStatusBox *tui.Box
 	workersUptimeBox *tui.Box
+	statusChart      *widgets.BarChart
+	historyLine      *widgets.Sparkline
 
 	OnSelectionChanged *mp.Signal
 }
 
-func NewWorkerListWidget(router *mp.Router) *WorkerListWidget {
+func NewWorkerListWidget(book *addressbook.Book, router *mp.Router) *WorkerListWidget {
 	workersList := widgets.NewList()
 	workersStatusBox := tui.NewVBox(tui.NewSpacer())
 	workersUptimeBox := tui.NewVBox(tui.NewSpacer())
 
-	workerETHAddrLabel := tui.NewLabel("ETH Address")
+	workerETHAddrLabel := tui.NewLabel("Worker")
 	workerETHAddrLabel.SetStyleName("highlight")
 
 	workerConfirmedLabel := tui.NewLabel("Confirmed")
@@ -196,17 +259,61 @@ func NewWorkerListWidget(router *mp.Router) *WorkerListWidget {
 		tui.NewSpacer(),
 	)
 
-	return &WorkerListWidget{
-		Box: tui.NewVBox(box, tui.NewSpacer()),
+	rows := &workerRows{
+		Box:  tui.NewVBox(box, tui.NewSpacer()),
+		list: workersList,
+	}
+
+	statusLabel := tui.NewLabel("Status")
+	statusLabel.SetStyleName("highlight")
+	statusChart := widgets.NewBarChart()
+	statusChart.SetFormatter(func(value float64) string {
+		return fmt.Sprintf("%d", int(value))
+	})
+
+	historyLabel := tui.NewLabel("History")
+	historyLabel.SetStyleName("highlight")
+	historyLine := widgets.NewSparkline()
+
+	chartsBox := tui.NewVBox(statusLabel, statusChart, historyLabel, historyLine)
+
+	m := &WorkerListWidget{
+		Box: tui.NewVBox(widgets.NewScrollView(rows), tui.NewPadder(1, 0, chartsBox)),
 
 		router: router,
+		book:   book,
 
 		workersList:      workersList,
 		workersStatusBox: workersStatusBox,
 		workersUptimeBox: workersUptimeBox,
+		statusChart:      statusChart,
+		historyLine:      historyLine,
 
 		OnSelectionChanged: router.NewSignal(),
 	}
+	m.updateStatusChart()
+
+	return m
+}
+
+// workerRows makes the worker table Scrollable, following the selected
+// worker below the header.
+type workerRows struct {
+	*tui.Box
+
+	list *widgets.List
+}
+
+func (m *workerRows) CursorRow() int {
+	if m.list.Selected() < 0 {
+		return -1
+	}
+
+	return m.list.Selected() + 2
+}
+
+func (m *workerRows) MoveCursor(rows int) {
+	m.list.MoveCursor(rows)
 }
 
 func (m *WorkerListWidget) pos(addr common.Address) int {
@@ -223,7 +330,7 @@ func (m *WorkerListWidget) pos(addr common.Address) int {
 
 func (m *WorkerListWidget) AddItem(item *workerItem) {
 	ctx := context.Background()
-	m.workersList.AddItems(item.Addr.Hex())
+	m.workersList.AddItems(m.book.Format(item.Addr))
 
 	var status *widgets.AsyncLabel
 	switch item.ConfirmationStatus {
@@ -241,6 +348,7 @@ func (m *WorkerListWidget) AddItem(item *workerItem) {
 
 	m.workersStatusBox.Append(tui.NewHBox(status))
 	m.workers = append(m.workers, item)
+	m.updateStatusChart()
 }
 
 func (m *WorkerListWidget) ReplaceItem(item *workerItem) {
@@ -267,6 +375,26 @@ func (m *WorkerListWidget) ReplaceItem(item *workerItem) {
 
 	m.workersStatusBox.Remove(pos)
 	m.workersStatusBox.Insert(pos, tui.NewHBox(status))
+	m.workers[pos] = item
+	m.updateStatusChart()
+}
+
+// SetHistory shows the number of workers over time.
+func (m *WorkerListWidget) SetHistory(values []float64) {
+	m.historyLine.SetValues(values)
+}
+
+func (m *WorkerListWidget) updateStatusChart() {
+	counts := map[confirmationStatus]int{}
+	for _, worker := range m.workers {
+		counts[worker.ConfirmationStatus]++
+	}
+
+	m.statusChart.SetBars(
+		widgets.Bar{Label: "Confirmed", Value: float64(counts[Confirmed])},
+		widgets.Bar{Label: "In progress", Value: float64(counts[InProgress])},
+		widgets.Bar{Label: "Unconfirmed", Value: float64(counts[Unconfirmed])},
+	)
 }
 
 func (m *WorkerListWidget) SetFocused(focused bool) {
@@ -281,8 +409,53 @@ func (m *WorkerListWidget) Selected() int {
 	return m.workersList.Selected()
 }
 
+// SelectedItem returns the hex address of the selected worker.
 func (m *WorkerListWidget) SelectedItem() string {
-	return m.workersList.SelectedItem()
+	selected := m.workersList.Selected()
+	if selected < 0 || selected >= len(m.workers) {
+		return ""
+	}
+
+	return m.workers[selected].Addr.Hex()
+}
+
+// UpdateNames redraws workers after the address book is changed.
+func (m *WorkerListWidget) UpdateNames() {
+	selected := m.workersList.Selected()
+
+	items := make([]string, 0, len(m.workers))
+	for _, worker := range m.workers {
+		items = append(items, m.book.Format(worker.Addr))
+	}
+	m.workersList.ReplaceItems(items...)
+
+	if selected >= 0 && selected < len(items) {
+		m.workersList.Select(selected)
+	}
+}
+
+// Search selects the worker best matching the pattern, as shown in the list,
+// and highlights matched runes of all matching workers. It returns false if
+// no worker matches.
+func (m *WorkerListWidget) Search(pattern string) bool {
+	items := make([]string, 0, len(m.workers))
+	for _, worker := range m.workers {
+		items = append(items, m.book.Format(worker.Addr))
+	}
+
+	m.workersList.ClearHighlights()
+
+	matches := widgets.FuzzyFilter(pattern, items)
+	if len(matches) == 0 {
+		return false
+	}
+
+	for _, match := range matches {
+		m.workersList.SetHighlights(match.Index, match.Positions)
+	}
+	m.workersList.Select(matches[0].Index)
+
+	return true
 }
 
 func (m *WorkerListWidget) Select(v int) {
@@ -297,6 +470,7 @@ func (m *WorkerListWidget) Clear() {
 		m.workersStatusBox.Remove(0)
 	}
 	m.workers = nil
+	m.updateStatusChart()
 }
 
 func (m *WorkerListWidget) OverrideOnKeyEvent(fn func(ev tui.KeyEvent) bool) {
@@ -305,15 +479,77 @@ func (m *WorkerListWidget) OverrideOnKeyEvent(fn func(ev tui.KeyEvent) bool) {
 
 // ========================================================================================================================
 
+const (
+	welcomeHint       = "Select previously used account and press <Enter> to specify password, <e> to rename or <Delete> to remove it, or use buttons below to login into other account, create, import or export one."
+	passwordHint      = "Enter password and press <Enter> to unlock the account, <Esc> to go back."
+	mainHint          = "Use arrows to navigate, <Enter> to select, <F4> to switch tabs. Press <F1> for help."
+	notificationsHint = "Use arrows, <PgUp>, <PgDn>, <Home> and <End> to scroll, <Esc> to go back."
+)
+
+// metricsInterval is the interval of polling the balance and the number of
+// workers for charts.
+const metricsInterval = 30 * time.Second
+
 type MainController struct {
-	view *MainView
+	node            string
+	view            *MainView
+	log             *logging.Logger
+	notify          *notify.Center
+	router          *mp.Router
+	focusController *interactions.FocusController
 
 	eventTxRx chan interface{}
+
+	// balanceHistory, workersHistory and histories of resources used by
+	// tasks are fed by polling while connected. The CPU usage is the rate of
+	// the CPU time consumed by tasks, in cores.
+	balanceHistory     *metrics.Series
+	workersHistory     *metrics.Series
+	tasksCPUHistory    *metrics.Series
+	tasksMemoryHistory *metrics.Series
+	tasksCPURate       *metrics.Rate
+
+	summaryMu sync.Mutex
+	summary   Summary
+
+	// OnOpenAccount, OnSwitchAccount, OnCreateAccount and OnLogout are
+	// emitted when the corresponding item of the accounts menu is activated.
+	OnOpenAccount   *mp.Signal
+	OnSwitchAccount *mp.Signal
+	OnCreateAccount *mp.Signal
+	OnLogout        *mp.Signal
+	// OnExit is emitted when the "Exit" menu item is activated.
+	OnExit *mp.Signal
+	// OnSummaryChanged is emitted with Summary each time the state of the
+	// connection, the balance or the number of workers changes.
+	OnSummaryChanged *mp.Signal
 }
 
-func NewMainController(ctx context.Context, view *MainView) *MainController {
+func NewMainController(ctx context.Context, node string, view *MainView, navigator *widgets.Navigator, dialogs *widgets.DialogStack, keys *keymap.Keymap, sess *session.Session, book *addressbook.Book, log *logging.Logger, notifications *notify.Center, router *mp.Router) *MainController {
 	eventTxRx := make(chan interface{}, 128)
 
+	onOpenAccount := router.NewSignal()
+	onSwitchAccount := router.NewSignal()
+	onCreateAccount := router.NewSignal()
+	onLogout := router.NewSignal()
+	onExit := router.NewSignal()
+
+	// Panels on the right are enabled while shown, so the focus moves there
+	// spatially from the menu.
+	focusScope := interactions.NewFocusScope(view.menuList, view.submenuList, view.workersView)
+	focusScope.SetWidgetEnabled(view.workersView, false)
+	focusController := interactions.NewFocusController(focusScope, router)
+	focusController.SetRoot(view)
+
+	focusSubmenu := func() {
+		focusController.Focus(view.submenuList)
+		view.submenuList.Select(0)
+	}
+	focusWorkers := func() {
+		focusController.Focus(view.workersView)
+		view.workersView.Select(0)
+	}
+
 	view.menuList.OnSelectionChanged(func(menu *tui.List) {
 		if menu.Selected() == -1 {
 			return
@@ -321,83 +557,183 @@ func NewMainController(ctx context.Context, view *MainView) *MainController {
 
 		switch menu.SelectedItem() {
 		case "Accounts":
-			view.submenuList.ReplaceItems("Switch", "Create", "Logout")
+			view.controlBox.Remove(1)
+			view.controlBox.Insert(1, view.submenuBox)
+			view.submenuList.ReplaceItems("Open", "Switch", "Create", "Transfer", "Logout")
+			focusScope.SetWidgetEnabled(view.submenuList, true)
+			focusScope.SetWidgetEnabled(view.workersView, false)
+		case "Market":
+			view.controlBox.Remove(1)
+			view.controlBox.Insert(1, view.submenuBox)
+			view.submenuList.ReplaceItems("Place Bid")
+			focusScope.SetWidgetEnabled(view.submenuList, true)
+			focusScope.SetWidgetEnabled(view.workersView, false)
 		case "Workers":
 			view.controlBox.Remove(1)
-			view.controlBox.Insert(1, view.workersView)
+			view.controlBox.Insert(1, view.workersPanel)
+			focusScope.SetWidgetEnabled(view.submenuList, false)
+			focusScope.SetWidgetEnabled(view.workersView, true)
 
 			eventTxRx <- &workersListUpdateEvent{}
 		default:
 			view.controlBox.Remove(1)
 			view.controlBox.Insert(1, view.submenuBox)
 			view.submenuList.ReplaceItems()
+			focusScope.SetWidgetEnabled(view.submenuList, false)
+			focusScope.SetWidgetEnabled(view.workersView, false)
 		}
 	})
 	view.menuList.OnItemActivated(func(menu *tui.List) {
 		switch menu.SelectedItem() {
+		case "Accounts", "Market":
+			focusSubmenu()
 		case "Workers":
-			view.menuList.SetFocused(false)
-			view.workersView.SetFocused(true)
-			view.workersView.Select(0)
+			focusWorkers()
 		case "Exit":
-			print("\033[H\033[2J")
-			os.Exit(0)
+			onExit.Emit(struct{}{})
 		default:
 		}
 	})
 	view.menuList.OnKeyEventX = func(ev tui.KeyEvent) bool {
-		switch ev.Key {
-		case tui.KeyRight:
-			selectedItem := view.menuList.SelectedItem()
-
-			switch selectedItem {
-			case "Workers":
-				view.menuList.SetFocused(false)
-				view.workersView.SetFocused(true)
-				view.workersView.Select(0)
-			default:
-				return false
-			}
-			return true
-		default:
-			return false
+		if keys.Match(keymap.FocusRight, ev) {
+			return focusController.FocusDirection(interactions.Right)
 		}
+
+		return false
+	}
+	view.submenuList.OnItemActivated(func(submenu *tui.List) {
+		switch view.menuList.SelectedItem() + "/" + submenu.SelectedItem() {
+		case "Accounts/Open":
+			onOpenAccount.Emit(struct{}{})
+		case "Accounts/Switch":
+			onSwitchAccount.Emit(struct{}{})
+		case "Accounts/Create":
+			onCreateAccount.Emit(struct{}{})
+		case "Accounts/Transfer":
+			form := NewFormView(router, "Transfer tokens", transferFields(), "[Transfer]")
+			showForm(form, transferHint, "transfer tokens", navigator, keys, sess, dialogs, notifications, router, func(values widgets.FormValues) {
+				eventTxRx <- &transferEvent{To: values.Address("to"), Amount: values.Amount("amount")}
+			})
+		case "Market/Place Bid":
+			form := NewFormView(router, "Place a bid order", bidOrderFields(), "[Place]")
+			showForm(form, bidOrderHint, "place the bid order", navigator, keys, sess, dialogs, notifications, router, func(values widgets.FormValues) {
+				eventTxRx <- &bidOrderEvent{
+					Price:      values.Price("price"),
+					Duration:   values.Duration("duration"),
+					Benchmarks: bidOrderBenchmarks(values),
+				}
+			})
+		case "Accounts/Logout":
+			dialog := widgets.NewConfirmDialog(router, "Logout", fmt.Sprintf("Log out of %s?", sess.Account().Hex()))
+			dialog.OnResult.Connect(func(v interface{}) {
+				if v.(*widgets.DialogResult).Accepted {
+					onLogout.Emit(struct{}{})
+				}
+			})
+			dialogs.Push(dialog)
+		}
+	})
+	view.submenuList.OnKeyEventX = func(ev tui.KeyEvent) bool {
+		if keys.Match(keymap.FocusLeft, ev) {
+			return focusController.FocusDirection(interactions.Left)
+		}
+
+		return false
 	}
 	view.workersView.OverrideOnKeyEvent(func(ev tui.KeyEvent) bool {
-		switch ev.Key {
-		case tui.KeyLeft:
-			view.menuList.SetFocused(true)
-			view.workersView.SetFocused(false)
-			view.workersView.Select(-1)
+		switch {
+		case keys.Match(keymap.FocusLeft, ev):
+			return focusController.FocusDirection(interactions.Left)
+		case keys.Match(keymap.ConfirmWorker, ev):
+			workerID := view.workersView.SelectedItem()
+
+			dialog := widgets.NewConfirmDialog(router, "Confirm Worker", fmt.Sprintf("Confirm worker %s?", workerID))
+			dialog.OnResult.Connect(func(v interface{}) {
+				if v.(*widgets.DialogResult).Accepted {
+					reauthenticate(sess, dialogs, notifications, router, "confirm the worker", func() {
+						eventTxRx <- &workerConfirmEvent{ID: workerID}
+					})
+				}
+			})
+			dialogs.Push(dialog)
 			return true
-		case tui.KeyRune:
-			switch ev.Rune {
-			case 'c':
-				eventTxRx <- &workerConfirmEvent{ID: view.workersView.SelectedItem()}
-				return true
+		case keys.Match(keymap.Refresh, ev):
+			eventTxRx <- &workersListUpdateEvent{}
+			return true
+		case keys.Match(keymap.Rename, ev):
+			if workerID := view.workersView.SelectedItem(); workerID != "" {
+				renameAddress(common.HexToAddress(workerID), book, dialogs, notifications, router, view.workersView.UpdateNames)
 			}
-			return false
+			return true
+		case keys.Match(keymap.Search, ev):
+			dialog := widgets.NewInputDialog(router, "Search Workers", "Enter a part of the address or the name of the worker.", tui.EchoModeNormal)
+			dialog.OnResult.Connect(func(v interface{}) {
+				result := v.(*widgets.DialogResult)
+				if !result.Accepted {
+					return
+				}
+
+				if pattern := strings.TrimSpace(result.Text); !view.workersView.Search(pattern) {
+					notifications.Info("No workers match %q", pattern)
+				}
+			})
+			dialogs.Push(dialog)
+			return true
 		default:
 			return false
 		}
 	})
 
+	help := keymap.NewContext()
+	help.RegisterFor(view.menuList, keymap.FocusRight, "Open the selected section")
+	help.RegisterFor(view.submenuList, keymap.FocusLeft, "Return to the menu")
+	help.RegisterFor(view.workersView, keymap.FocusLeft, "Return to the menu")
+	help.RegisterFor(view.workersView, keymap.ConfirmWorker, "")
+	help.RegisterFor(view.workersView, keymap.Refresh, "Reload the worker list")
+	help.RegisterFor(view.workersView, keymap.Rename, "Name the selected worker in the address book")
+	help.RegisterFor(view.workersView, keymap.Search, "Find a worker by its address or name")
+	navigator.SetContext(view, help)
+
 	m := &MainController{
-		view:      view,
-		eventTxRx: eventTxRx,
+		node:            node,
+		view:            view,
+		log:             log,
+		notify:          notifications,
+		router:          router,
+		focusController: focusController,
+		eventTxRx:       eventTxRx,
+
+		balanceHistory:     metrics.NewSeries(metrics.DefaultHistorySize),
+		workersHistory:     metrics.NewSeries(metrics.DefaultHistorySize),
+		tasksCPUHistory:    metrics.NewSeries(metrics.DefaultHistorySize),
+		tasksMemoryHistory: metrics.NewSeries(metrics.DefaultHistorySize),
+		tasksCPURate:       metrics.NewRate(),
+
+		OnOpenAccount:    onOpenAccount,
+		OnSwitchAccount:  onSwitchAccount,
+		OnCreateAccount:  onCreateAccount,
+		OnLogout:         onLogout,
+		OnExit:           onExit,
+		OnSummaryChanged: router.NewSignal(),
 	}
 
 	go m.run(ctx)
 
 	m.view.menuList.Select(0)
-	m.view.menuList.SetFocused(true)
+	focusController.Focus(view.menuList)
 
 	return m
 }
 
 func (m *MainController) run(ctx context.Context) {
 	addr := common.Address{}
-	var nodeConn *grpc.ClientConn
+	var client *rpc.Client
+
+	// Each connection lives in its own context, which is canceled on
+	// reconnect or when the session is closed to stop everything bound to
+	// the connection, including the certificate rotator holding the key.
+	connID := 0
+	connCtx, connCancel := context.WithCancel(ctx)
 
 	workersConfirmationInProgress := map[string]struct{}{}
 	workersConfirmed := map[string]struct{}{}
@@ -405,38 +741,77 @@ func (m *MainController) run(ctx context.Context) {
 	workerStatusTimer := util.NewImmediateTicker(60 * time.Second)
 	defer workerStatusTimer.Stop()
 
+	metricsTimer := time.NewTicker(metricsInterval)
+	defer metricsTimer.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
+			connCancel()
+			if client != nil {
+				client.Close()
+			}
 			return
 		case ev := <-m.eventTxRx:
 			switch event := ev.(type) {
 			case *nodeConnectEvent:
-				addr = crypto.PubkeyToAddress(event.PrivateKey.PublicKey)
-				m.connectToNodeAsync(ctx, event.Addr, event.PrivateKey)
+				connCancel()
+				if client != nil {
+					client.Close()
+					client = nil
+				}
+
+				connID++
+				connCtx, connCancel = context.WithCancel(ctx)
+
+				addr = event.Signer.Account()
+				m.log.Info("connecting to the node", logging.F("node", event.Addr), logging.F("account", addr.Hex()))
+				m.resetHistory()
+				m.updateSummary(func(summary *Summary) {
+					*summary = Summary{Account: addr, Node: event.Addr, Workers: -1}
+				})
+				m.connectToNodeAsync(connCtx, connID, event.Addr, event.Signer)
 			case *nodeConnectionResultEvent:
+				if event.ID != connID {
+					// The connection is no longer wanted, for example the
+					// session was locked while connecting.
+					if event.Conn != nil {
+						event.Conn.Close()
+					}
+					continue
+				}
+
 				if event.Error != nil {
+					m.log.Error("failed to connect to the node", logging.F("node", event.Addr), logging.Err(event.Error))
+					m.notify.Error("Failed to connect to %s: %v", event.Addr, event.Error)
 					m.view.currentNodeVLabel.StopProgress(event.Error.Error())
 					continue
 				}
 
-				nodeConn = event.Conn
-				m.onNodeConnected(ctx, event.Conn, addr)
+				m.log.Info("connected to the node", logging.F("node", event.Addr), logging.F("account", addr.Hex()))
+				m.updateSummary(func(summary *Summary) {
+					summary.Connected = true
+				})
+				client = rpc.NewClient(event.Conn, addr, m.log)
+				go m.watchConnection(connCtx, event.Conn, event.Addr)
+				m.onNodeConnected(connCtx, client)
 				m.eventTxRx <- &workersListUpdateEvent{}
 			case *workersListUpdateEvent:
-				if nodeConn != nil {
+				if client != nil {
 					pos := m.view.workersView.Selected()
-					node := sonm.NewMasterManagementClient(nodeConn)
-					workers, err := node.WorkersList(ctx, &sonm.EthAddress{
-						Address: addr.Bytes(),
-					})
+					workers, err := client.Workers(connCtx)
 					if err != nil {
-						return
+						m.log.Error("failed to update worker list", logging.Err(err))
+						m.notify.Error("Failed to update worker list: %v", err)
+						continue
 					}
 
 					m.view.workersView.Clear()
+					m.updateSummary(func(summary *Summary) {
+						summary.Workers = len(workers)
+					})
 
-					for _, worker := range workers.GetWorkers() {
+					for _, worker := range workers {
 						workerItem := &workerItem{
 							Addr:               worker.GetSlaveID().Unwrap(),
 							ConfirmationStatus: Unconfirmed,
@@ -453,11 +828,6 @@ func (m *MainController) run(ctx context.Context) {
 
 					m.view.workersView.workersStatusBox.Append(tui.NewSpacer())
 					m.view.workersView.Select(pos)
-				} else {
-					go func() {
-						time.Sleep(1 * time.Second)
-						m.eventTxRx <- &workersListUpdateEvent{}
-					}()
 				}
 			case *workerConfirmEvent:
 				workerAddr := common.HexToAddress(event.ID)
@@ -465,23 +835,74 @@ func (m *MainController) run(ctx context.Context) {
 					continue
 				}
 
-				if nodeConn != nil {
-					node := sonm.NewMasterManagementClient(nodeConn)
+				if client != nil {
 					workersConfirmationInProgress[event.ID] = struct{}{}
 
 					m.view.workersView.ReplaceItem(&workerItem{Addr: common.HexToAddress(event.ID), ConfirmationStatus: InProgress})
 
-					go func() {
-						_, err := node.WorkerConfirm(ctx, sonm.NewEthAddress(workerAddr))
+					go func(ctx context.Context, id int) {
+						err := client.ConfirmWorker(ctx, workerAddr)
 
-						m.eventTxRx <- &workerConfirmDoneEvent{ID: event.ID, Error: err}
-					}()
+						m.eventTxRx <- &workerConfirmDoneEvent{ConnID: id, ID: event.ID, Error: err}
+					}(connCtx, connID)
 				}
 			case *workerConfirmDoneEvent:
+				if event.ConnID != connID {
+					// The confirmation was interrupted by disconnecting.
+					continue
+				}
+
+				if event.Error != nil {
+					m.log.Error("failed to confirm worker", logging.F("worker", event.ID), logging.Err(event.Error))
+					m.notify.Error("Failed to confirm worker %s: %v", event.ID, event.Error)
+				} else {
+					m.log.Info("worker confirmed", logging.F("worker", event.ID))
+					m.notify.Success("Worker %s confirmed", event.ID)
+				}
+
 				m.eventTxRx <- &workersListUpdateEvent{}
 				delete(workersConfirmationInProgress, event.ID)
+			case *transferEvent:
+				if client == nil {
+					m.notify.Error("Failed to transfer tokens: not connected to the node")
+					continue
+				}
+
+				go func(ctx context.Context, client *rpc.Client) {
+					amount := units.FormatAmount(event.Amount)
+					if err := client.Transfer(ctx, event.To, event.Amount); err != nil {
+						m.log.Error("failed to transfer tokens", logging.F("to", event.To.Hex()), logging.Err(err))
+						m.notify.Error("Failed to transfer %s to %s: %v", amount, event.To.Hex(), err)
+						return
+					}
+
+					m.log.Info("tokens transferred", logging.F("to", event.To.Hex()), logging.F("amount", amount))
+					m.notify.Success("Transferred %s to %s", amount, event.To.Hex())
+				}(connCtx, client)
+			case *bidOrderEvent:
+				if client == nil {
+					m.notify.Error("Failed to place the bid order: not connected to the node")
+					continue
+				}
+
+				go func(ctx context.Context, client *rpc.Client) {
+					order, err := client.CreateBidOrder(ctx, event.Price, event.Duration, event.Benchmarks)
+					if err != nil {
+						m.log.Error("failed to place bid order", logging.Err(err))
+						m.notify.Error("Failed to place the bid order: %v", err)
+						return
+					}
+
+					m.log.Info("bid order placed", logging.F("order", order.GetId().Unwrap().String()))
+					m.notify.Success("Bid order %s placed at %s", order.GetId().Unwrap().String(), units.FormatPrice(event.Price))
+					m.eventTxRx <- &ordersUpdateEvent{}
+				}(connCtx, client)
+			case *ordersUpdateEvent:
+				if client != nil {
+					m.updateOrderCount(connCtx, client)
+				}
 			case *workersUpdateUptimeEvent:
-				if nodeConn != nil {
+				if client != nil {
 					//node := sonm.NewWorkerManagementClient(nodeConn)
 					//
 					//for addr := range workersConfirmed {
@@ -511,87 +932,245 @@ func (m *MainController) run(ctx context.Context) {
 			}
 		case <-workerStatusTimer.C:
 			m.eventTxRx <- &workersUpdateUptimeEvent{}
+		case <-metricsTimer.C:
+			if client != nil {
+				m.pollMetrics(connCtx, client)
+			}
 		}
 	}
 }
 
-func (m *MainController) connectToNodeAsync(ctx context.Context, addr string, privateKey *ecdsa.PrivateKey) {
+func (m *MainController) connectToNodeAsync(ctx context.Context, id int, addr string, s signer.Signer) {
 	m.view.currentNodeVLabel.RunProgress(ctx)
 
-	fn := func() (*grpc.ClientConn, error) {
-		_, TLSConfig, err := util.NewHitlessCertRotator(ctx, privateKey)
-		if err != nil {
-			return nil, err
-		}
-
-		credentials := auth.NewWalletAuthenticator(util.NewTLS(TLSConfig), crypto.PubkeyToAddress(privateKey.PublicKey))
-
-		ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-		defer cancel()
-
-		return xgrpc.NewClient(ctx, addr, credentials, grpc.WithBlock())
-	}
-
 	go func() {
-		conn, err := fn()
+		conn, err := rpc.Dial(ctx, addr, s)
 		if err != nil {
-			m.eventTxRx <- &nodeConnectionResultEvent{Addr: addr, Error: err}
+			m.eventTxRx <- &nodeConnectionResultEvent{ID: id, Addr: addr, Error: err}
 		} else {
-			m.eventTxRx <- &nodeConnectionResultEvent{Addr: addr, Conn: conn}
+			m.eventTxRx <- &nodeConnectionResultEvent{ID: id, Addr: addr, Conn: conn}
 		}
 	}()
 }
 
-func (m *MainController) onNodeConnected(ctx context.Context, conn *grpc.ClientConn, addr common.Address) {
-	m.view.currentNodeVLabel.StopProgress(conn.Target())
+// watchConnection notifies when the connection to the node is lost or
+// restored until the context is canceled or the connection is closed.
+func (m *MainController) watchConnection(ctx context.Context, conn *grpc.ClientConn, addr string) {
+	lost := false
+	state := conn.GetState()
+
+	for conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+
+		switch state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			if !lost {
+				lost = true
+				m.log.Warn("connection to the node lost", logging.F("node", addr), logging.F("state", state))
+				m.notify.Error("Connection to %s lost", addr)
+				m.updateSummary(func(summary *Summary) {
+					summary.Connected = false
+				})
+			}
+			if state == connectivity.Shutdown {
+				return
+			}
+		case connectivity.Ready:
+			if lost {
+				lost = false
+				m.log.Info("connection to the node restored", logging.F("node", addr))
+				m.notify.Success("Connection to %s restored", addr)
+				m.updateSummary(func(summary *Summary) {
+					summary.Connected = true
+				})
+			}
+		}
+	}
+}
 
-	m.view.currentAccountVLabel.SetText(addr.Hex())
-	m.view.currentBalanceVLabel.SetTextAsync(ctx, func(ctx context.Context) string {
-		node := sonm.NewTokenManagementClient(conn)
-		balance, err := node.BalanceOf(ctx, &sonm.EthAddress{
-			Address: addr.Bytes(),
-		})
+// resetHistory forgets metrics of the previous connection.
+func (m *MainController) resetHistory() {
+	m.balanceHistory.Reset()
+	m.workersHistory.Reset()
+	m.tasksCPUHistory.Reset()
+	m.tasksMemoryHistory.Reset()
+	m.tasksCPURate.Reset()
+
+	m.router.Execute(func() {
+		m.view.balancePlot.SetValues(nil)
+		m.view.workersView.SetHistory(nil)
+		m.view.tasksCPUVLabel.SetText("-")
+		m.view.tasksCPULine.SetValues(nil)
+		m.view.tasksMemoryVLabel.SetText("-")
+		m.view.tasksMemoryLine.SetValues(nil)
+	})
+}
+
+func (m *MainController) onNodeConnected(ctx context.Context, client *rpc.Client) {
+	m.view.currentNodeVLabel.StopProgress(client.Target())
+
+	m.view.currentAccountVLabel.SetText(client.Account().Hex())
+	m.pollMetrics(ctx, client)
+	m.updateOrderCount(ctx, client)
+
+	m.view.dealCountVLabel.SetTextAsync(ctx, func(ctx context.Context) string {
+		_, count, err := client.Deals(ctx)
 		if err != nil {
 			return err.Error()
 		}
 
-		return balance.SideBalance.ToPriceString()
+		return fmt.Sprintf("%d", count)
 	})
+}
+
+func (m *MainController) updateOrderCount(ctx context.Context, client *rpc.Client) {
 	m.view.orderCountVLabel.SetTextAsync(ctx, func(ctx context.Context) string {
-		node := sonm.NewMarketClient(conn)
-		orders, err := node.GetOrders(ctx, &sonm.Count{})
+		orders, err := client.Orders(ctx)
 		if err != nil {
 			return err.Error()
 		}
 
-		return fmt.Sprintf("%d", len(orders.GetOrders()))
+		return fmt.Sprintf("%d", len(orders))
 	})
+}
 
-	m.view.dealCountVLabel.SetTextAsync(ctx, func(ctx context.Context) string {
-		node := sonm.NewDWHClient(conn)
-		orders, err := node.GetDeals(ctx, &sonm.DealsRequest{
-			Status:    sonm.DealStatus_DEAL_ACCEPTED,
-			AnyUserID: &sonm.EthAddress{Address: addr.Bytes()},
-			WithCount: true,
-		})
+// pollMetrics updates the balance and samples it along with the number of
+// workers and resource usage of tasks into the history shown on charts.
+func (m *MainController) pollMetrics(ctx context.Context, client *rpc.Client) {
+	m.view.currentBalanceVLabel.SetTextAsync(ctx, func(ctx context.Context) string {
+		balance, err := client.Balance(ctx)
 		if err != nil {
 			return err.Error()
 		}
 
-		return fmt.Sprintf("%d", orders.GetCount())
+		if ctx.Err() == nil {
+			m.updateSummary(func(summary *Summary) {
+				summary.Balance = balance.GetSideBalance().Unwrap()
+			})
+
+			m.balanceHistory.Add(units.AmountFloat(balance.GetSideBalance().Unwrap()))
+			m.router.Execute(func() {
+				m.view.balancePlot.SetValues(m.balanceHistory.Values())
+			})
+		}
+
+		return balance.GetSideBalance().ToPriceString()
 	})
+
+	go func() {
+		// Failures are logged by the client, the sample is just skipped.
+		workers, err := client.Workers(ctx)
+		if err != nil || ctx.Err() != nil {
+			return
+		}
+
+		m.workersHistory.Add(float64(len(workers)))
+		m.router.Execute(func() {
+			m.view.workersView.SetHistory(m.workersHistory.Values())
+		})
+	}()
+
+	go func() {
+		sampledAt := time.Now()
+		cpu, memory, err := taskUsage(ctx, client)
+		if err != nil || ctx.Err() != nil {
+			return
+		}
+
+		m.tasksMemoryHistory.Add(memory)
+		// The rate of the CPU time in nanoseconds per second is the number
+		// of cores used.
+		rate, ok := m.tasksCPURate.Add(cpu, sampledAt)
+		cores := rate / float64(time.Second)
+		if ok {
+			m.tasksCPUHistory.Add(cores)
+		}
+
+		m.router.Execute(func() {
+			m.view.tasksMemoryVLabel.SetText(units.FormatSize(uint64(memory)))
+			m.view.tasksMemoryLine.SetValues(m.tasksMemoryHistory.Values())
+			if ok {
+				m.view.tasksCPUVLabel.SetText(fmt.Sprintf("%.2f cores", cores))
+				m.view.tasksCPULine.SetValues(m.tasksCPUHistory.Values())
+			}
+		})
+	}()
 }
 
-func (m *MainController) SetAccount(privateKey *ecdsa.PrivateKey) {
-	m.eventTxRx <- &nodeConnectEvent{Addr: "localhost:15030", PrivateKey: privateKey}
+// taskUsage sums resources used by tasks running within deals the account
+// consumes: the CPU time consumed in nanoseconds, which is cumulative, and
+// the peak memory usage in bytes. Tasks of deals the account supplies can't
+// be listed.
+//
+// Failures are logged by the client, so a failure listing tasks of any deal
+// just fails the whole sample instead of making it incomplete.
+func taskUsage(ctx context.Context, client *rpc.Client) (float64, float64, error) {
+	deals, _, err := client.Deals(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cpu, memory := 0.0, 0.0
+	for _, deal := range deals {
+		if deal.GetDeal().GetConsumerID().Unwrap() != client.Account() {
+			continue
+		}
+
+		tasks, err := client.Tasks(ctx, deal.GetDeal().GetId().Unwrap())
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for _, task := range tasks {
+			cpu += float64(task.GetUsage().GetCpu().GetTotal())
+			memory += float64(task.GetUsage().GetMemory().GetMaxUsage())
+		}
+	}
+
+	return cpu, memory, nil
+}
+
+// SetSigner connects to the node on behalf of the signer account, starting
+// from the menu.
+func (m *MainController) SetSigner(s signer.Signer) {
+	// The view might be left focused elsewhere by the previous connection.
+	m.focusController.Focus(m.view.menuList)
+
+	m.eventTxRx <- &nodeConnectEvent{Addr: m.node, Signer: s}
+}
+
+// Summary returns the current state of the connection.
+func (m *MainController) Summary() Summary {
+	m.summaryMu.Lock()
+	defer m.summaryMu.Unlock()
+
+	return m.summary
+}
+
+func (m *MainController) updateSummary(fn func(summary *Summary)) {
+	m.summaryMu.Lock()
+	fn(&m.summary)
+	summary := m.summary
+	m.summaryMu.Unlock()
+
+	m.OnSummaryChanged.Emit(summary)
 }
 
 // ------------------------------------------------------------------------
+
+// maxWelcomeAccounts is the number of recent accounts shown at once on the
+// welcome screen, the rest are scrolled.
+const maxWelcomeAccounts = 8
+
 type WelcomeView struct {
 	*widgets.FocusBox
 
 	accountsList     *widgets.List
+	accountsView     *widgets.ScrollView
 	loginOtherButton *tui.Button
+	createButton     *tui.Button
+	importButton     *tui.Button
+	exportButton     *tui.Button
 }
 
 func NewWelcomeView() *WelcomeView {
@@ -599,18 +1178,26 @@ func NewWelcomeView() *WelcomeView {
 	logoLabel.SetStyleName("logo")
 
 	accountsList := widgets.NewList()
+	accountsView := widgets.NewScrollView(accountsList)
+	accountsView.SetMaxVisible(maxWelcomeAccounts)
 	loginOtherButton := tui.NewButton("[Login Other]")
+	createButton := tui.NewButton("[Create]")
+	importButton := tui.NewButton("[Import]")
+	exportButton := tui.NewButton("[Export]")
 
 	buttonsBox := tui.NewHBox(
 		tui.NewSpacer(),
-		//tui.NewPadder(1, 0, loginOtherButton),
+		tui.NewPadder(1, 0, interactions.NewAnchor(loginOtherButton)),
+		tui.NewPadder(1, 0, interactions.NewAnchor(createButton)),
+		tui.NewPadder(1, 0, interactions.NewAnchor(importButton)),
+		tui.NewPadder(1, 0, interactions.NewAnchor(exportButton)),
 		tui.NewSpacer(),
 	)
 
 	windowBox := tui.NewVBox(
 		tui.NewPadder(0, 1, logoLabel),
 		tui.NewPadder(0, 0, tui.NewLabel(welcomeText)),
-		tui.NewPadder(0, 1, accountsList),
+		tui.NewPadder(0, 1, interactions.NewAnchor(accountsView)),
 		buttonsBox,
 	)
 
@@ -626,7 +1213,11 @@ func NewWelcomeView() *WelcomeView {
 		FocusBox: widgets.NewFocusBox(contentBox, nil),
 
 		accountsList:     accountsList,
+		accountsView:     accountsView,
 		loginOtherButton: loginOtherButton,
+		createButton:     createButton,
+		importButton:     importButton,
+		exportButton:     exportButton,
 	}
 }
 
@@ -635,91 +1226,199 @@ func (m *WelcomeView) Hide() {
 }
 
 type WelcomeController struct {
+	view            *WelcomeView
+	navigator       *widgets.Navigator
+	focusScope      *interactions.FocusScope
+	focusController *interactions.FocusController
+	cfg             *config.Config
+	theme           *tui.Theme
+	// accounts are addresses in the order of list items.
+	accounts []common.Address
+
+	// OnLogin, OnRename and OnRemove are emitted with the address of the
+	// selected account.
 	OnLogin      *mp.Signal
 	OnLoginOther *mp.Signal
+	OnCreate     *mp.Signal
+	OnImport     *mp.Signal
+	OnExport     *mp.Signal
+	OnRename     *mp.Signal
+	OnRemove     *mp.Signal
 }
 
-func NewWelcomeController(view *WelcomeView, router *mp.Router, accounts map[common.Address]string) *WelcomeController {
-	for account := range accounts {
-		view.accountsList.AddItems(account.Hex())
+func NewWelcomeController(view *WelcomeView, navigator *widgets.Navigator, keys *keymap.Keymap, router *mp.Router, cfg *config.Config, theme *tui.Theme) *WelcomeController {
+	// The list is focusable only when there is something to select, which
+	// is decided by Refresh.
+	focusScope := interactions.NewFocusScope(
+		view.accountsView,
+		view.loginOtherButton,
+		view.createButton,
+		view.importButton,
+		view.exportButton,
+	)
+	focusScope.SetWidgetEnabled(view.accountsView, false)
+	focusController := interactions.NewFocusController(focusScope, router)
+
+	m := &WelcomeController{
+		view:            view,
+		navigator:       navigator,
+		focusScope:      focusScope,
+		focusController: focusController,
+		cfg:             cfg,
+		theme:           theme,
+
+		OnLogin:      router.NewSignal(),
+		OnLoginOther: router.NewSignal(),
+		OnCreate:     router.NewSignal(),
+		OnImport:     router.NewSignal(),
+		OnExport:     router.NewSignal(),
+		OnRename:     router.NewSignal(),
+		OnRemove:     router.NewSignal(),
 	}
 
-	focusChain := interactions.NewFocusChain()
-	focusController := interactions.NewFocusController(focusChain)
+	view.accountsList.OnKeyEventX = func(ev tui.KeyEvent) bool {
+		switch {
+		case keys.Match(keymap.NextFocus, ev):
+			focusController.FocusNextWidget()
+		case keys.Match(keymap.Rename, ev):
+			m.emitSelected(m.OnRename)
+		case keys.Match(keymap.Remove, ev):
+			m.emitSelected(m.OnRemove)
+		default:
+			return false
+		}
 
-	if view.accountsList.Length() > 0 {
-		focusChain.AddWidget(view.accountsList)
+		return true
+	}
+
+	view.accountsList.OnItemActivated(func(*tui.List) { m.emitSelected(m.OnLogin) })
+	view.loginOtherButton.OnActivated(func(*tui.Button) { m.OnLoginOther.Emit(struct{}{}) })
+	view.createButton.OnActivated(func(*tui.Button) { m.OnCreate.Emit(struct{}{}) })
+	view.importButton.OnActivated(func(*tui.Button) { m.OnImport.Emit(struct{}{}) })
+	view.exportButton.OnActivated(func(*tui.Button) { m.OnExport.Emit(struct{}{}) })
+
+	view.SetFocusController(focusController)
+	view.SetKeymap(keys)
+
+	help := keymap.NewContext()
+	help.Register(keymap.NextFocus, "")
+	help.Register(keymap.PrevFocus, "")
+	help.RegisterFor(view.accountsList, keymap.Rename, "Rename the selected account")
+	help.RegisterFor(view.accountsList, keymap.Remove, "Remove the selected account from the list")
+	navigator.SetContext(view, help)
+
+	m.Refresh()
+	focusController.FocusDefaultWidget()
+
+	return m
+}
+
+func (m *WelcomeController) emitSelected(signal *mp.Signal) {
+	if selected := m.view.accountsList.Selected(); selected >= 0 && selected < len(m.accounts) {
+		signal.Emit(m.accounts[selected])
+	}
+}
 
-		view.accountsList.OnKeyEventX = func(ev tui.KeyEvent) bool {
-			switch ev.Key {
-			case tui.KeyTab:
-				focusController.FocusNextWidget()
-				return true
-			default:
-				return false
+// Refresh rebuilds the list of previously used accounts from the config,
+// the most recently used first.
+func (m *WelcomeController) Refresh() {
+	list := m.view.accountsList
+	selected := list.Selected()
+
+	m.accounts = m.cfg.RecentAccounts()
+
+	labelWidth := 0
+	for _, account := range m.accounts {
+		if width := utf8.RuneCountInString(m.cfg.AccountInfo[account].Label); width > labelWidth {
+			labelWidth = width
+		}
+	}
+
+	list.RemoveItems()
+	for id, account := range m.accounts {
+		info := m.cfg.AccountInfo[account]
+		list.AddItems(accountItem(account, info, labelWidth))
+
+		if info.Color != "" {
+			// Invalid colors are ignored rather than failing the start,
+			// since the account is still usable.
+			if style, err := themes.ColorStyle(m.theme, info.Color); err == nil {
+				list.SetItemStyle(id, style)
 			}
 		}
 	}
 
-	onLogin := router.NewSignal()
-	onLoginOther := router.NewSignal()
+	m.focusScope.SetWidgetEnabled(m.view.accountsView, len(m.accounts) > 0)
+	if len(m.accounts) == 0 && m.focusController.FocusedWidget == m.view.accountsView {
+		m.focusController.FocusNextWidget()
+	}
 
-	view.accountsList.OnItemActivated(func(menu *tui.List) { onLogin.Emit(menu.SelectedItem()) })
-	view.loginOtherButton.OnActivated(func(*tui.Button) { onLoginOther.Emit(struct{}{}) })
+	if list.IsFocused() && len(m.accounts) > 0 {
+		if selected < 0 || selected >= len(m.accounts) {
+			selected = len(m.accounts) - 1
+		}
+		list.Select(selected)
+	}
+}
 
-	focusChain.AddWidget(view.loginOtherButton)
-	focusController.FocusDefaultWidget()
+// accountItem formats the list item of the account, aligning labels to the
+// given width.
+func accountItem(account common.Address, info config.AccountInfo, labelWidth int) string {
+	lastUsed := "never used"
+	if !info.LastUsed.IsZero() {
+		lastUsed = "used " + formatAge(time.Since(info.LastUsed))
+	}
 
-	view.SetFocusController(focusController)
+	if labelWidth == 0 {
+		return fmt.Sprintf("%s  %s", account.Hex(), lastUsed)
+	}
+
+	padding := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(info.Label))
+	return fmt.Sprintf("%s%s  %s  %s", info.Label, padding, account.Hex(), lastUsed)
+}
 
-	return &WelcomeController{
-		OnLogin:      onLogin,
-		OnLoginOther: onLoginOther,
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", age/time.Minute)
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", age/time.Hour)
+	default:
+		return fmt.Sprintf("%dd ago", age/(24*time.Hour))
 	}
 }
 
+// Show makes the welcome view the only screen.
+func (m *WelcomeController) Show() {
+	m.Refresh()
+	m.navigator.Reset(m.view, welcomeHint)
+}
+
 // ------------------------------------------------------------------------
 
 type PasswordView struct {
-	*widgets.FocusBox
+	*tui.Box
 
-	accountVLabel *tui.Label
-	entry         *widgets.Entry
-	unlockButton  *tui.Button
-	cancelButton  *tui.Button
+	form *widgets.Form
 }
 
-func NewPasswordView() *PasswordView {
+func NewPasswordView(router *mp.Router) *PasswordView {
 	helpLabel := tui.NewLabel("Enter password for the account")
 	helpLabel.SetStyleName("title")
 
-	accountLabel := tui.NewLabel("Account:")
-	accountLabel.SetStyleName("highlight")
-	accountVLabel := tui.NewLabel("")
-	passwordLabel := tui.NewLabel("Password:")
-	passwordLabel.SetStyleName("highlight")
-	passwordEntry := widgets.NewEntry()
-	passwordEntry.SetEchoMode(tui.EchoModePassword)
-	passwordEntry.SetSizeHint(image.Point{X: 32, Y: 1})
-
-	unlockButton := tui.NewButton("[Unlock]")
-	cancelButton := tui.NewButton("[Cancel]")
-
-	contentBox := tui.NewVBox(
-		tui.NewPadder(1, 1, helpLabel),
-		tui.NewHBox(
-			tui.NewVBox(tui.NewPadder(1, 0, accountLabel), tui.NewPadder(1, 0, passwordLabel)),
-			tui.NewVBox(accountVLabel, passwordEntry),
-			tui.NewSpacer(),
-		),
-		tui.NewHBox(tui.NewSpacer(), tui.NewPadder(1, 0, unlockButton), cancelButton),
-	)
+	form := widgets.NewForm(router, []widgets.FormField{
+		{Name: "account", Label: "Account", Kind: widgets.AddressField, ReadOnly: true},
+		{Name: "password", Label: "Password", Kind: widgets.PasswordField},
+	}, "[Unlock]", "[Cancel]")
 
 	box := tui.NewHBox(
 		tui.NewSpacer(),
 		tui.NewVBox(
 			tui.NewSpacer(),
-			contentBox,
+			tui.NewPadder(1, 1, helpLabel),
+			tui.NewPadder(1, 0, form),
 			tui.NewSpacer(),
 			tui.NewSpacer(),
 		),
@@ -727,177 +1426,359 @@ func NewPasswordView() *PasswordView {
 	)
 
 	return &PasswordView{
-		FocusBox: widgets.NewFocusBox(box, nil),
-
-		accountVLabel: accountVLabel,
-		entry:         passwordEntry,
-		unlockButton:  unlockButton,
-		cancelButton:  cancelButton,
+		Box:  box,
+		form: form,
 	}
 }
 
-func (m *PasswordView) SetFocused(focused bool) {
-	m.entry.SetFocused(focused)
+func (m *PasswordView) SetFocused(v bool) {
+	m.form.SetFocused(v)
 }
 
 type PasswordController struct {
-	view            *PasswordView
-	focusController *interactions.FocusController
+	view      *PasswordView
+	navigator *widgets.Navigator
+	router    *mp.Router
+
+	// unlockID identifies the current unlock attempt, so results of attempts
+	// abandoned by leaving or resetting the view are dropped.
+	unlockID  int
+	unlocking bool
 
+	// OnSubmit is emitted with widgets.FormValues holding the password.
 	OnSubmit *mp.Signal
 	OnCancel *mp.Signal
 }
 
-func NewPasswordController(view *PasswordView, router *mp.Router) *PasswordController {
-	focusChain := interactions.NewFocusChain()
-	focusChain.AddWidget(view.entry)
-	focusChain.AddWidget(view.unlockButton)
-	focusChain.AddWidget(view.cancelButton)
+func NewPasswordController(view *PasswordView, navigator *widgets.Navigator, keys *keymap.Keymap, router *mp.Router) *PasswordController {
+	view.form.SetKeymap(keys)
 
-	focusController := interactions.NewFocusController(focusChain)
-	focusController.FocusDefaultWidget()
+	m := &PasswordController{
+		view:      view,
+		navigator: navigator,
+		router:    router,
 
-	onSubmit := router.NewSignal()
-	onCancel := router.NewSignal()
+		OnSubmit: view.form.OnSubmit,
+		OnCancel: router.NewSignal(),
+	}
 
-	view.entry.OnSubmit(func(entry *tui.Entry) {
-		onSubmit.Emit(entry.Text())
-	})
-	view.unlockButton.OnActivated(func(*tui.Button) {
-		onSubmit.Emit(view.entry.Text())
-	})
-	view.cancelButton.OnActivated(func(*tui.Button) {
-		onCancel.Emit(struct{}{})
+	// Leaving the view either way, by the cancel button or by going back,
+	// abandons the current attempt.
+	navigator.OnRemoved(view, m.abandonUnlock)
+	view.form.OnCancel.Connect(func(interface{}) {
+		navigator.Pop()
+		m.OnCancel.Emit(struct{}{})
 	})
 
-	view.SetFocusController(focusController)
+	help := keymap.NewContext()
+	help.Register(keymap.NextFocus, "")
+	help.Register(keymap.PrevFocus, "")
+	view.form.RegisterInputs(help)
+	navigator.SetContext(view, help)
 
-	return &PasswordController{
-		view:            view,
-		focusController: focusController,
+	return m
+}
 
-		OnSubmit: onSubmit,
-		OnCancel: onCancel,
+// Unlock decrypts the key of the current account from the keystore at the
+// given path in the background, since decryption is deliberately slow. The
+// function is called on the UI thread with the result, unless the attempt
+// was abandoned meanwhile. Submissions are ignored while unlocking.
+func (m *PasswordController) Unlock(path, password string, fn func(s *signer.KeySigner, err error)) {
+	if m.unlocking {
+		return
 	}
+	m.unlocking = true
+
+	id := m.unlockID
+	account := m.CurrentAccount()
+
+	go func() {
+		s, err := signer.NewKeystoreSigner(path, account, password)
+
+		m.router.Execute(func() {
+			if id != m.unlockID {
+				if s != nil {
+					s.Close()
+				}
+				return
+			}
+
+			m.unlocking = false
+			fn(s, err)
+		})
+	}()
+}
+
+func (m *PasswordController) abandonUnlock() {
+	m.unlockID++
+	m.unlocking = false
 }
 
 func (m *PasswordController) Reset() {
-	m.view.accountVLabel.SetText("")
-	m.view.entry.SetText("")
-	m.focusController.FocusDefaultWidget()
+	m.abandonUnlock()
+	m.view.form.SetText("account", "")
+	m.view.form.SetText("password", "")
+	m.view.form.Reset()
 }
 
 func (m *PasswordController) CurrentAccount() common.Address {
-	return common.HexToAddress(m.view.accountVLabel.Text())
+	return common.HexToAddress(m.view.form.Text("account"))
 }
 
 func (m *PasswordController) SetAccount(account common.Address) {
-	m.view.accountVLabel.SetText(account.Hex())
+	m.view.form.SetText("account", account.Hex())
+}
+
+// Show opens the password view for the given account on top of the current
+// screen.
+func (m *PasswordController) Show(account common.Address) {
+	m.Reset()
+	m.SetAccount(account)
+	m.navigator.Push(m.view, passwordHint)
 }
 
 // ------------------------------------------------------------------------
 
-func exec() error {
-	cfg, err := config.LoadConfig(config.DefaultConfigPath)
+func exec(ctx context.Context, cfg *config.Config, opts *options, log *logging.Logger) error {
+	keys, err := keymap.NewKeymap(cfg.Keymap)
 	if err != nil {
-		if os.IsNotExist(err) {
-			cfg = config.NewConfig()
-		} else {
-			return err
-		}
+		return err
+	}
+
+	theme, err := themes.Load(cfg.Theme)
+	if err != nil {
+		return err
+	}
+
+	book, err := addressbook.Load(addressbook.PathFor(opts.ConfigPath))
+	if err != nil {
+		return fmt.Errorf("failed to load address book: %v", err)
 	}
 
-	ctx := context.Background()
 	router := mp.NewRouter()
 
+	notifications, err := newNotificationCenter(cfg.Notifications, router)
+	if err != nil {
+		return err
+	}
+
 	welcomeView := NewWelcomeView()
-	passwordView := NewPasswordView()
-	loginView := views.NewLoginView()
-	mainView := NewMainView(ctx, router)
+	passwordView := NewPasswordView(router)
+	loginView := views.NewLoginView(router)
 
-	statusBar := tui.NewStatusBar("Select previously used account and press <Enter> to specify password or select <Login Other> button to login into other account.")
+	statusBar := tui.NewStatusBar("")
 	statusBar.SetPermanentText(version.Version)
 
-	root := tui.NewVBox(
-		welcomeView,
-		statusBar,
-	)
+	navigator := widgets.NewNavigator(statusBar)
+	navigator.SetKeymap(keys)
+	console := widgets.NewLogConsole(log, router)
+	toaster := widgets.NewToaster(navigator, router)
+	layout := tui.NewVBox(toaster)
+	dialogs := widgets.NewDialogStack(layout)
+	dialogs.SetKeymap(keys)
+
+	workspace := NewWorkspace(ctx, cfg, book, navigator, dialogs, keys, log, notifications, router)
+	defer workspace.Close()
 
-	ui, err := tui.New(root)
+	ui, err := tui.New(widgets.NewKeyObserver(dialogs, func(tui.KeyEvent) {
+		workspace.Touch()
+	}))
 	if err != nil {
 		return err
 	}
 
+	history := widgets.NewNotificationHistory(nil)
+	notifications.OnPosted.Connect(func(v interface{}) {
+		notification := v.(notify.Notification)
+		toaster.Show(notification)
+		history.Add(notification)
+	})
+
 	// Controllers.
 
-	welcomeController := NewWelcomeController(welcomeView, router, cfg.AccountPaths)
-	passwordController := NewPasswordController(passwordView, router)
-	loginController := views.NewLoginController(loginView, router)
-	mainController := NewMainController(ctx, mainView)
+	welcomeController := NewWelcomeController(welcomeView, navigator, keys, router, cfg, theme)
+	passwordController := NewPasswordController(passwordView, navigator, keys, router)
+	loginController := views.NewLoginController(loginView, navigator, keys, book, router)
 
 	welcomeController.OnLogin.Connect(func(v interface{}) {
-		passwordController.Reset()
-		passwordController.SetAccount(common.HexToAddress(v.(string)))
-
-		ui.SetWidget(tui.NewVBox(
-			passwordView,
-			statusBar,
-		))
+		passwordController.Show(v.(common.Address))
 	})
 	welcomeController.OnLoginOther.Connect(func(interface{}) {
-		loginController.Reset()
+		loginController.Show()
+	})
 
-		ui.SetWidget(tui.NewVBox(
-			loginView,
-			statusBar,
-		))
-		statusBar.SetText("Specify directory with keystore. <Tab> for completion, <Enter> for submitting")
+	accountsController := NewAccountsController(cfg, opts.ConfigPath, dialogs, notifications, log, router)
+	welcomeController.OnCreate.Connect(func(interface{}) {
+		accountsController.Create()
+	})
+	welcomeController.OnImport.Connect(func(interface{}) {
+		accountsController.Import()
+	})
+	welcomeController.OnExport.Connect(func(interface{}) {
+		accountsController.Export()
+	})
+	welcomeController.OnRename.Connect(func(v interface{}) {
+		accountsController.Rename(v.(common.Address))
+	})
+	welcomeController.OnRemove.Connect(func(v interface{}) {
+		accountsController.Remove(v.(common.Address))
+	})
+	accountsController.OnAdded.Connect(func(interface{}) {
+		welcomeController.Refresh()
+	})
+	accountsController.OnChanged.Connect(func(interface{}) {
+		welcomeController.Refresh()
+	})
+
+	workspace.OnOpenAccount.Connect(func(v interface{}) {
+		passwordController.Show(v.(common.Address))
+	})
+	workspace.OnCreateAccount.Connect(func(interface{}) {
+		accountsController.Create()
+	})
+	workspace.OnEmpty.Connect(func(interface{}) {
+		welcomeController.Show()
+	})
+	workspace.OnExit.Connect(func(interface{}) {
+		ui.Quit()
 	})
 
 	passwordController.OnSubmit.Connect(func(v interface{}) {
 		account := passwordController.CurrentAccount()
-		password := v.(string)
+		password := v.(widgets.FormValues).String("password")
 		path, ok := cfg.AccountPaths[account]
 		if !ok {
-			statusBar.SetText(err.Error())
+			// Accounts unlocked using the login screen are not necessarily
+			// known by the config.
+			path, ok = workspace.KeystorePath(account)
+		}
+		if !ok {
+			log.Error("unknown account", logging.F("account", account.Hex()))
+			notifications.Error("Unknown account %s", account.Hex())
 			return
 		}
 
-		keystore, err := accounts.NewMultiKeystore(accounts.NewKeystoreConfig(path), accounts.NewStaticPassPhraser(""))
-		if err != nil {
-			statusBar.SetText(err.Error())
-			return
+		passwordController.Unlock(path, password, func(s *signer.KeySigner, err error) {
+			if err != nil {
+				log.Warn("failed to unlock account", logging.F("account", account.Hex()), logging.Err(err))
+				notifications.Error("Failed to unlock %s: %v", account.Hex(), err)
+				return
+			}
+
+			log.Info("account unlocked", logging.F("account", account.Hex()))
+			workspace.Open(path, s)
+			accountsController.Touch(account)
+		})
+	})
+
+	loginController.OnUnlocked.Connect(func(v interface{}) {
+		s := signer.NewKeySigner(v.(*ecdsa.PrivateKey))
+		workspace.Open(loginController.KeystorePath(), s)
+		accountsController.Touch(s.Account())
+	})
+
+	workspace.OnLocked.Connect(func(v interface{}) {
+		event := v.(*session.Locked)
+
+		for dialogs.Length() > 0 {
+			dialogs.Pop()
+		}
+		welcomeController.Show()
+		if path, _ := workspace.KeystorePath(event.Account); path != "" {
+			passwordController.Show(event.Account)
 		}
 
-		privateKey, err := keystore.GetKeyWithPass(account, password)
+		log.Info("session locked", logging.F("account", event.Account.Hex()), logging.F("reason", event.Reason))
+		if event.Reason == session.LockIdle {
+			notifications.Info("Session locked due to inactivity")
+		} else {
+			notifications.Info("Session locked")
+		}
+	})
+
+	welcomeController.Show()
+
+	switch {
+	case opts.Signer != signer.KindKeystore:
+		s, err := newSigner(cfg, opts)
 		if err != nil {
-			statusBar.SetText(err.Error())
+			return err
+		}
+
+		workspace.Open("", s)
+		accountsController.Touch(s.Account())
+	case opts.Account != "":
+		passwordController.Show(common.HexToAddress(opts.Account))
+	case opts.Keystore != "":
+		loginController.Show()
+		loginController.SetKeystorePath(opts.Keystore)
+	}
+
+	showHelp := func() {
+		if dialogs.Length() > 0 {
 			return
 		}
 
-		mainController.SetAccount(privateKey)
+		bindings := navigator.Context().Bindings()
+		if navigator.Depth() > 1 {
+			bindings = append(bindings, keymap.Binding{Action: keymap.Back, Description: keymap.Describe(keymap.Back)})
+		}
+		bindings = append(bindings,
+			keymap.Binding{Action: keymap.ToggleLog, Description: keymap.Describe(keymap.ToggleLog)},
+			keymap.Binding{Action: keymap.Notifications, Description: keymap.Describe(keymap.Notifications)},
+			keymap.Binding{Action: keymap.NextTab, Description: keymap.Describe(keymap.NextTab)},
+			keymap.Binding{Action: keymap.Lock, Description: keymap.Describe(keymap.Lock)},
+			keymap.Binding{Action: keymap.Help, Description: keymap.Describe(keymap.Help)},
+			keymap.Binding{Action: keymap.Quit, Description: keymap.Describe(keymap.Quit)},
+		)
+
+		dialogs.Push(widgets.NewHelpDialog(router, "Help", keys, bindings))
+	}
 
-		ui.SetWidget(tui.NewVBox(
-			mainView,
-			statusBar,
-		))
-	})
-	passwordController.OnCancel.Connect(func(v interface{}) {
-		ui.SetWidget(tui.NewVBox(
-			welcomeView,
-			statusBar,
-		))
-	})
+	toggleLog := func() {
+		if layout.Length() > 1 {
+			layout.Remove(1)
+		} else {
+			layout.Append(console)
+		}
+	}
 
-	loginController.OnCancel.Connect(func(v interface{}) {
-		ui.SetWidget(tui.NewVBox(
-			welcomeView,
-			statusBar,
-		))
-	})
+	showNotifications := func() {
+		if dialogs.Length() == 0 && navigator.Current() != history {
+			navigator.Push(history, notificationsHint)
+		}
+	}
 
-	ui.SetTheme(DefaultTheme())
-	ui.SetKeybinding("Ctrl+C", ui.Quit)
+	ui.SetTheme(theme)
+	for _, chord := range keys.Chords(keymap.Lock) {
+		ui.SetKeybinding(chord, func() {
+			workspace.Lock(session.LockManual)
+		})
+	}
+	for _, chord := range keys.Chords(keymap.NextTab) {
+		ui.SetKeybinding(chord, workspace.NextTab)
+	}
+	for _, chord := range keys.Chords(keymap.Notifications) {
+		ui.SetKeybinding(chord, showNotifications)
+	}
+	for _, chord := range keys.Chords(keymap.ToggleLog) {
+		ui.SetKeybinding(chord, toggleLog)
+	}
+	for _, chord := range keys.Chords(keymap.Quit) {
+		ui.SetKeybinding(chord, ui.Quit)
+	}
+	for _, chord := range keys.Chords(keymap.Help) {
+		if utf8.RuneCountInString(chord) == 1 {
+			// Rune chords, like "?", must still be typeable into text inputs.
+			ui.SetKeybinding(chord, func() {
+				if !navigator.Context().InputFocused() {
+					showHelp()
+				}
+			})
+		} else {
+			ui.SetKeybinding(chord, showHelp)
+		}
+	}
 
 	go func() {
 		for fn := range router.Rx() {
@@ -912,8 +1793,147 @@ func exec() error {
 	return nil
 }
 
+// renameAddress asks for the name of the address to store in the address
+// book, removing the address when the name is empty, and calls done after
+// the book is changed.
+func renameAddress(addr common.Address, book *addressbook.Book, dialogs *widgets.DialogStack, notifications *notify.Center, router *mp.Router, done func()) {
+	entry, _ := book.Lookup(addr)
+
+	dialog := widgets.NewInputDialog(router, "Address Book", fmt.Sprintf("Enter name for %s, leave empty to remove it from the address book.", addr.Hex()), tui.EchoModeNormal)
+	dialog.SetText(entry.Name)
+	dialog.OnResult.Connect(func(v interface{}) {
+		result := v.(*widgets.DialogResult)
+		if !result.Accepted {
+			return
+		}
+
+		var err error
+		if entry.Name = strings.TrimSpace(result.Text); entry.Name == "" {
+			err = book.Remove(addr)
+		} else {
+			err = book.Set(addr, entry)
+		}
+
+		if err != nil {
+			notifications.Error("Failed to save the address book: %v", err)
+		}
+		done()
+	})
+	dialogs.Push(dialog)
+}
+
+// reauthenticate asks for the password of the session account and runs the
+// given sensitive action on the UI thread only if the password is correct.
+//
+// Sessions not backed by a keystore have no password to ask for, so the
+// action is run right away, as long as the session is unlocked. Callers
+// confirm the action with the user on their own.
+func reauthenticate(sess *session.Session, dialogs *widgets.DialogStack, notifications *notify.Center, router *mp.Router, action string, fn func()) {
+	if sess.KeystorePath() == "" {
+		// There is nothing to decrypt, so verifying is quick enough for the
+		// UI thread.
+		if err := sess.Verify(""); err != nil {
+			notifications.Error("Authentication failed: %v", err)
+			return
+		}
+
+		fn()
+		return
+	}
+
+	text := fmt.Sprintf("Enter password of %s to %s.", sess.Account().Hex(), action)
+
+	dialog := widgets.NewInputDialog(router, "Authentication Required", text, tui.EchoModePassword)
+	dialog.OnResult.Connect(func(v interface{}) {
+		result := v.(*widgets.DialogResult)
+		if !result.Accepted {
+			return
+		}
+
+		go func() {
+			if err := sess.Verify(result.Text); err != nil {
+				notifications.Error("Authentication failed: %v", err)
+				return
+			}
+
+			router.Execute(fn)
+		}()
+	})
+	dialogs.Push(dialog)
+}
+
+// newNotificationCenter constructs the notification center with bell and
+// desktop hooks enabled in the config.
+func newNotificationCenter(cfg config.NotificationsConfig, router *mp.Router) (*notify.Center, error) {
+	center := notify.NewCenter(router)
+
+	if cfg.Bell != "" {
+		severity, err := notify.ParseSeverity(cfg.Bell)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bell severity: %v", err)
+		}
+
+		center.AddHook(notify.NewBellHook(os.Stdout, severity))
+	}
+
+	if cfg.Desktop != "" {
+		severity, err := notify.ParseSeverity(cfg.Desktop)
+		if err != nil {
+			return nil, fmt.Errorf("invalid desktop notification severity: %v", err)
+		}
+
+		center.AddHook(notify.NewDesktopHook(cfg.DesktopCommand, severity))
+	}
+
+	return center, nil
+}
+
 func main() {
-	if err := exec(); err != nil {
-		os.Exit(1)
+	opts, args, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(exitUsage)
+	}
+
+	if err := run(opts, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitCode(err))
+	}
+}
+
+func run(opts *options, args []string) error {
+	if execStandaloneCommand(args) {
+		return nil
+	}
+
+	log, logFile, err := setupLogging(opts.LogFile, opts.LogLevel)
+	if err != nil {
+		return err
+	}
+	if logFile != nil {
+		defer logFile.Close()
 	}
+
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		log.Error("failed to load config", logging.F("path", opts.ConfigPath), logging.Err(err))
+		return err
+	}
+
+	ctx := context.Background()
+
+	if len(args) > 0 {
+		err = execCommand(ctx, cfg, opts, log, args)
+	} else {
+		log.Info("starting", logging.F("version", version.Version))
+		err = exec(ctx, cfg, opts, log)
+	}
+
+	if err != nil {
+		log.Error("exited with error", logging.Err(err))
+	}
+
+	return err
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+func validateOutputFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return &usageError{fmt.Errorf("unknown output format %q, expected one of: %s", format, strings.Join(outputFormats, ", "))}
+}
+
+// result is an outcome of a non-interactive command.
+//
+// Value is what gets encoded as JSON or YAML, so it should consist of records
+// with tagged fields. Header and Rows are used for the table output.
+type result struct {
+	Header []string
+	Rows   [][]string
+	Value  interface{}
+}
+
+func (m *result) AddRow(cells ...string) {
+	m.Rows = append(m.Rows, cells)
+}
+
+func printResult(wr io.Writer, format string, res *result) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(wr)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(res.Value)
+	case outputYAML:
+		data, err := yaml.Marshal(res.Value)
+		if err != nil {
+			return err
+		}
+
+		_, err = wr.Write(data)
+		return err
+	default:
+		tw := tabwriter.NewWriter(wr, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(res.Header, "\t"))
+		for _, row := range res.Rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+	}
+}
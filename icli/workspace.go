@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/3Hren/sonmui/icli/internal/addressbook"
+	"github.com/3Hren/sonmui/icli/internal/config"
+	"github.com/3Hren/sonmui/icli/internal/keymap"
+	"github.com/3Hren/sonmui/icli/internal/logging"
+	"github.com/3Hren/sonmui/icli/internal/mp"
+	"github.com/3Hren/sonmui/icli/internal/notify"
+	"github.com/3Hren/sonmui/icli/internal/session"
+	"github.com/3Hren/sonmui/icli/internal/signer"
+	"github.com/3Hren/sonmui/icli/internal/widgets"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/marcusolsson/tui-go"
+	sonm "github.com/sonm-io/core/proto"
+)
+
+// Summary describes the state of a single session for the overview.
+type Summary struct {
+	Account   common.Address
+	Node      string
+	Connected bool
+	// Balance is nil until it is received.
+	Balance *big.Int
+	// Workers is -1 until the worker list is received.
+	Workers int
+}
+
+// OverviewView is a table of all open sessions with their balances and
+// worker counts summed up.
+type OverviewView struct {
+	*tui.Box
+
+	columns    *tui.Box
+	scrollView *widgets.ScrollView
+}
+
+func NewOverviewView() *OverviewView {
+	columns := tui.NewHBox()
+	scrollView := widgets.NewScrollView(tui.NewVBox(tui.NewPadder(1, 0, columns), tui.NewSpacer()))
+
+	box := tui.NewVBox(scrollView)
+	box.SetBorder(true)
+	box.SetTitle("Overview")
+
+	m := &OverviewView{
+		Box:        box,
+		columns:    columns,
+		scrollView: scrollView,
+	}
+	m.SetSummaries(nil)
+
+	return m
+}
+
+func (m *OverviewView) SetFocused(v bool) {
+	m.Box.SetFocused(v)
+	m.scrollView.SetFocused(v)
+}
+
+// SetSummaries replaces the table contents.
+func (m *OverviewView) SetSummaries(summaries []Summary) {
+	header := []string{"Account", "Node", "Status", "Balance", "Workers"}
+	rows := make([][]string, 0, len(summaries)+1)
+
+	total := new(big.Int)
+	totalWorkers := 0
+	connected := 0
+	for _, summary := range summaries {
+		status := "connecting"
+		if summary.Connected {
+			status = "connected"
+			connected++
+		}
+
+		balance := "-"
+		if summary.Balance != nil {
+			balance = sonm.NewBigInt(summary.Balance).ToPriceString()
+			total.Add(total, summary.Balance)
+		}
+
+		workers := "-"
+		if summary.Workers >= 0 {
+			workers = fmt.Sprintf("%d", summary.Workers)
+			totalWorkers += summary.Workers
+		}
+
+		rows = append(rows, []string{summary.Account.Hex(), summary.Node, status, balance, workers})
+	}
+	rows = append(rows, []string{
+		"Total",
+		"",
+		fmt.Sprintf("%d/%d connected", connected, len(summaries)),
+		sonm.NewBigInt(total).ToPriceString(),
+		fmt.Sprintf("%d", totalWorkers),
+	})
+
+	for m.columns.Length() > 0 {
+		m.columns.Remove(0)
+	}
+
+	for col, name := range header {
+		nameLabel := tui.NewLabel(name)
+		nameLabel.SetStyleName("highlight")
+
+		column := tui.NewVBox(nameLabel)
+		for id, row := range rows {
+			label := tui.NewLabel(row[col])
+			if id == len(rows)-1 {
+				label.SetStyleName("bold")
+			}
+			column.Append(label)
+		}
+
+		m.columns.Append(tui.NewPadder(1, 0, column))
+	}
+	m.columns.Append(tui.NewSpacer())
+}
+
+type workspaceSession struct {
+	account    common.Address
+	sess       *session.Session
+	view       *MainView
+	controller *MainController
+	cancel     context.CancelFunc
+}
+
+// Workspace shows sessions of several accounts at once, each with its own
+// connection and key, as tabs following the overview.
+//
+// Locking, either manually or on idle, closes all sessions, asking for the
+// password of the account whose session was locked.
+type Workspace struct {
+	ctx       context.Context
+	cfg       *config.Config
+	book      *addressbook.Book
+	tabs      *widgets.Tabs
+	overview  *OverviewView
+	sessions  []*workspaceSession
+	keystores map[common.Address]string
+
+	navigator *widgets.Navigator
+	dialogs   *widgets.DialogStack
+	keys      *keymap.Keymap
+	log       *logging.Logger
+	notify    *notify.Center
+	router    *mp.Router
+
+	// OnOpenAccount is emitted with the address of an account that should be
+	// unlocked and opened in a new tab.
+	OnOpenAccount   *mp.Signal
+	OnCreateAccount *mp.Signal
+	// OnEmpty is emitted when the last session is closed by logging out.
+	OnEmpty *mp.Signal
+	// OnLocked is emitted with session.Locked after all sessions are closed
+	// because one of them was locked.
+	OnLocked *mp.Signal
+	OnExit   *mp.Signal
+}
+
+func NewWorkspace(ctx context.Context, cfg *config.Config, book *addressbook.Book, navigator *widgets.Navigator, dialogs *widgets.DialogStack, keys *keymap.Keymap, log *logging.Logger, notifications *notify.Center, router *mp.Router) *Workspace {
+	overview := NewOverviewView()
+
+	tabs := widgets.NewTabs()
+	tabs.Append("Overview", overview)
+
+	return &Workspace{
+		ctx:       ctx,
+		cfg:       cfg,
+		book:      book,
+		tabs:      tabs,
+		overview:  overview,
+		keystores: map[common.Address]string{},
+
+		navigator: navigator,
+		dialogs:   dialogs,
+		keys:      keys,
+		log:       log,
+		notify:    notifications,
+		router:    router,
+
+		OnOpenAccount:   router.NewSignal(),
+		OnCreateAccount: router.NewSignal(),
+		OnEmpty:         router.NewSignal(),
+		OnLocked:        router.NewSignal(),
+		OnExit:          router.NewSignal(),
+	}
+}
+
+// Open connects to the node on behalf of the signer account in a new tab and
+// shows the workspace. The signer is owned by the workspace from now on.
+//
+// The keystore path is empty for signers not backed by a keystore. Such
+// sessions are not locked on idle, because they can't be unlocked again by
+// asking for the password.
+//
+// If the account is already open, its session is reconnected instead.
+func (m *Workspace) Open(keystorePath string, sgn signer.Signer) {
+	account := sgn.Account()
+	m.keystores[account] = keystorePath
+
+	if s := m.find(account); s != nil {
+		s.sess.Unlock(account, keystorePath, sgn)
+		s.controller.SetSigner(sgn)
+		m.tabs.SetCurrent(s.view)
+		m.Show()
+		return
+	}
+
+	timeout := m.cfg.Session.IdleTimeout
+	if keystorePath == "" {
+		timeout = 0
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	sess := session.NewSession(timeout, signer.LoadKey, m.router)
+	view := NewMainView(ctx, m.book, m.router)
+	controller := NewMainController(ctx, m.cfg.NodeOf(account), view, m.navigator, m.dialogs, m.keys, sess, m.book, m.log, m.notify, m.router)
+
+	s := &workspaceSession{
+		account:    account,
+		sess:       sess,
+		view:       view,
+		controller: controller,
+		cancel:     cancel,
+	}
+
+	controller.OnOpenAccount.Connect(func(interface{}) {
+		m.chooseAccount("Open Account", "Select account to open in a new tab.", func(account common.Address) {
+			m.OnOpenAccount.Emit(account)
+		})
+	})
+	controller.OnSwitchAccount.Connect(func(interface{}) {
+		m.chooseAccount("Switch Account", "Select account to switch to.", func(account common.Address) {
+			m.logout(s)
+			m.OnOpenAccount.Emit(account)
+		})
+	})
+	controller.OnCreateAccount.Connect(func(interface{}) {
+		m.OnCreateAccount.Emit(struct{}{})
+	})
+	controller.OnLogout.Connect(func(interface{}) {
+		m.logout(s)
+		m.notify.Info("Logged out of %s", s.account.Hex())
+	})
+	controller.OnExit.Connect(func(interface{}) {
+		m.OnExit.Emit(struct{}{})
+	})
+	controller.OnSummaryChanged.Connect(func(interface{}) {
+		m.updateOverview()
+	})
+	sess.OnLocked.Connect(func(v interface{}) {
+		m.onLocked(s, v.(*session.Locked))
+	})
+
+	sess.Unlock(account, keystorePath, sgn)
+	controller.SetSigner(sgn)
+
+	m.sessions = append(m.sessions, s)
+	m.tabs.Append(shortAddress(account), view)
+	m.tabs.SetCurrent(view)
+	m.updateOverview()
+	m.Show()
+}
+
+// Show makes the workspace the only screen.
+func (m *Workspace) Show() {
+	m.navigator.Reset(m.tabs, mainHint)
+}
+
+// NextTab switches to the next tab if the workspace is visible.
+func (m *Workspace) NextTab() {
+	if m.navigator.Current() == m.tabs && m.dialogs.Length() == 0 {
+		m.tabs.Next()
+	}
+}
+
+// Touch resets the idle timeout of all sessions.
+func (m *Workspace) Touch() {
+	for _, s := range m.sessions {
+		s.sess.Touch()
+	}
+}
+
+// Lock locks the session of the current tab, which closes all sessions.
+func (m *Workspace) Lock(reason session.LockReason) {
+	if len(m.sessions) == 0 {
+		return
+	}
+
+	current := m.sessions[0]
+	for _, s := range m.sessions {
+		if s.view == m.tabs.CurrentPage() {
+			current = s
+		}
+	}
+
+	current.sess.Lock(reason)
+}
+
+// Close zeroes keys of all sessions and disconnects them without notifying
+// anyone, which is meant to be done on exit.
+func (m *Workspace) Close() {
+	for len(m.sessions) > 0 {
+		m.remove(m.sessions[0])
+	}
+}
+
+// KeystorePath returns the keystore of the given account if it was opened
+// at least once.
+func (m *Workspace) KeystorePath(account common.Address) (string, bool) {
+	path, ok := m.keystores[account]
+	return path, ok
+}
+
+func (m *Workspace) find(account common.Address) *workspaceSession {
+	for _, s := range m.sessions {
+		if s.account == account {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// chooseAccount asks to select one of configured accounts that are not open
+// yet.
+func (m *Workspace) chooseAccount(title, text string, fn func(account common.Address)) {
+	accounts := make([]string, 0, len(m.cfg.AccountPaths))
+	for account := range m.cfg.AccountPaths {
+		if m.find(account) == nil {
+			accounts = append(accounts, account.Hex())
+		}
+	}
+	sort.Strings(accounts)
+
+	if len(accounts) == 0 {
+		m.notify.Warn("All configured accounts are already open")
+		return
+	}
+
+	dialog := widgets.NewChoiceDialog(m.router, title, text, accounts...)
+	dialog.OnResult.Connect(func(v interface{}) {
+		result := v.(*widgets.DialogResult)
+		if result.Accepted && result.Choice >= 0 {
+			fn(common.HexToAddress(accounts[result.Choice]))
+		}
+	})
+	m.dialogs.Push(dialog)
+}
+
+func (m *Workspace) logout(s *workspaceSession) {
+	m.remove(s)
+	m.log.Info("logged out", logging.F("account", s.account.Hex()))
+
+	if len(m.sessions) == 0 {
+		m.OnEmpty.Emit(struct{}{})
+	}
+}
+
+func (m *Workspace) onLocked(s *workspaceSession, event *session.Locked) {
+	if m.find(s.account) != s {
+		// Already closed along with the other session that was locked.
+		return
+	}
+
+	m.Close()
+	m.OnLocked.Emit(event)
+}
+
+// remove zeroes the key of the session and tears down its connection along
+// with everything bound to it.
+func (m *Workspace) remove(s *workspaceSession) {
+	for id, other := range m.sessions {
+		if other == s {
+			m.sessions = append(m.sessions[:id], m.sessions[id+1:]...)
+			break
+		}
+	}
+
+	s.sess.Close()
+	s.cancel()
+
+	m.tabs.Remove(s.view)
+	m.navigator.RemoveContext(s.view)
+	m.updateOverview()
+}
+
+func (m *Workspace) updateOverview() {
+	summaries := make([]Summary, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		summary := s.controller.Summary()
+		if summary.Account == (common.Address{}) {
+			summary.Account = s.account
+		}
+		summaries = append(summaries, summary)
+	}
+
+	m.overview.SetSummaries(summaries)
+}
+
+func shortAddress(account common.Address) string {
+	hex := account.Hex()
+	return hex[:10] + "…"
+}